@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// OrderExpiryWorker cancels unpaid orders past their payment deadline every
+// interval until ctx is done.
+func OrderExpiryWorker(ctx context.Context, orders usecase.OrderService, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := orders.CancelExpiredOrders(ctx)
+			if err != nil {
+				logger.Error("failed to cancel expired orders", zap.Error(err))
+			} else if n > 0 {
+				logger.Info("cancelled expired orders", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// ShipmentTrackingWorker syncs the tracking status of shipments with their
+// carriers every interval until ctx is done.
+func ShipmentTrackingWorker(ctx context.Context, orders usecase.OrderService, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := orders.SyncShipmentTracking(ctx)
+			if err != nil {
+				logger.Error("failed to sync shipment tracking", zap.Error(err))
+			} else if n > 0 {
+				logger.Info("delivered shipments", zap.Int("count", n))
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package adaptor
 
 import (
+	"errors"
 	"net/http"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
 	"project-app-ecommerce-golang-tim-1/internal/usecase"
@@ -60,7 +61,7 @@ func (h *HandlerAddress) Update(ctx *gin.Context) {
 	customerID, _ := uid.(uint)
 	res, err := h.Service.UpdateAddress(ctx.Request.Context(), id, req, customerID)
 	if err != nil {
-		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		response.ResponseBadRequest(ctx, addressErrorStatus(err), err.Error())
 		return
 	}
 	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
@@ -73,7 +74,7 @@ func (h *HandlerAddress) Delete(ctx *gin.Context) {
 	uid, _ := ctx.Get("userID")
 	customerID, _ := uid.(uint)
 	if err := h.Service.DeleteAddress(ctx.Request.Context(), id, customerID); err != nil {
-		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		response.ResponseBadRequest(ctx, addressErrorStatus(err), err.Error())
 		return
 	}
 	response.ResponseSuccess(ctx, http.StatusOK, "deleted", nil)
@@ -86,8 +87,19 @@ func (h *HandlerAddress) SetDefault(ctx *gin.Context) {
 	uid, _ := ctx.Get("userID")
 	customerID, _ := uid.(uint)
 	if err := h.Service.SetDefaultAddress(ctx.Request.Context(), customerID, id); err != nil {
-		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		response.ResponseBadRequest(ctx, addressErrorStatus(err), err.Error())
 		return
 	}
 	response.ResponseSuccess(ctx, http.StatusOK, "set default", nil)
 }
+
+// addressErrorStatus maps address ownership errors to 404 and 403.
+func addressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrAddressNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrAddressForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,88 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerGiftCard struct {
+	GiftCard usecase.GiftCardService
+	Logger   *zap.Logger
+}
+
+func NewHandlerGiftCard(giftCard usecase.GiftCardService, logger *zap.Logger) HandlerGiftCard {
+	return HandlerGiftCard{GiftCard: giftCard, Logger: logger}
+}
+
+func (h *HandlerGiftCard) Issue(ctx *gin.Context) {
+	var req dto.IssueGiftCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	uid, _ := ctx.Get("userID")
+	adminID, _ := uid.(uint)
+	res, err := h.GiftCard.IssueGiftCard(ctx.Request.Context(), adminID, req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+// Generate creates a batch of gift cards with random codes.
+func (h *HandlerGiftCard) Generate(ctx *gin.Context) {
+	var req dto.BulkGiftCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	uid, _ := ctx.Get("userID")
+	adminID, _ := uid.(uint)
+	res, err := h.GiftCard.GenerateGiftCards(ctx.Request.Context(), adminID, req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerGiftCard) List(ctx *gin.Context) {
+	var q dto.GiftCardListQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.GiftCard.ListGiftCards(ctx.Request.Context(), q)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerGiftCard) Get(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	res, err := h.GiftCard.GetGiftCard(ctx.Request.Context(), uint(id64))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+// Balance lets a customer check a gift card code before using it.
+func (h *HandlerGiftCard) Balance(ctx *gin.Context) {
+	res, err := h.GiftCard.CheckBalance(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
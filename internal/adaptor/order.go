@@ -1,11 +1,16 @@
 package adaptor
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
 	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
 	"project-app-ecommerce-golang-tim-1/pkg/response"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -30,19 +35,41 @@ func (h *HandlerOrder) CreateOrder(ctx *gin.Context) {
 	customerID, _ := uid.(uint)
 	res, err := h.Order.CreateOrder(ctx.Request.Context(), req, customerID)
 	if err != nil {
-		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		response.ResponseBadRequest(ctx, addressErrorStatus(err), err.Error())
 		return
 	}
 	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
 }
 
+// Quote previews checkout totals for the request without placing the order.
+func (h *HandlerOrder) Quote(ctx *gin.Context) {
+	var req dto.CreateOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	res, err := h.Order.QuoteOrder(ctx.Request.Context(), req, customerID)
+	if err != nil {
+		response.ResponseBadRequest(ctx, addressErrorStatus(err), err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "quote", res)
+}
+
+// GetOrderDetail accepts either the numeric order id or the order number.
 func (h *HandlerOrder) GetOrderDetail(ctx *gin.Context) {
 	idStr := ctx.Param("id")
-	id64, _ := strconv.ParseUint(idStr, 10, 64)
-	id := uint(id64)
 	uid, _ := ctx.Get("userID")
 	customerID, _ := uid.(uint)
-	res, err := h.Order.GetOrderDetail(ctx.Request.Context(), id, customerID)
+	var res *dto.OrderResponse
+	var err error
+	if id64, perr := strconv.ParseUint(idStr, 10, 64); perr == nil {
+		res, err = h.Order.GetOrderDetail(ctx.Request.Context(), uint(id64), customerID)
+	} else {
+		res, err = h.Order.GetOrderDetailByNumber(ctx.Request.Context(), idStr, customerID)
+	}
 	if err != nil {
 		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
 		return
@@ -80,3 +107,239 @@ func (h *HandlerOrder) Cart(ctx *gin.Context) {
 	}
 	response.ResponseSuccess(ctx, http.StatusOK, "cart", res)
 }
+
+// Reorder copies a past order back into the cart ("buy again").
+func (h *HandlerOrder) Reorder(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	res, err := h.Order.Reorder(ctx.Request.Context(), uint(id64), customerID)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "reordered", res)
+}
+
+// AdminList lists orders for admins, searchable by order number.
+func (h *HandlerOrder) AdminList(ctx *gin.Context) {
+	var q dto.AdminOrderListQuery
+	_ = ctx.ShouldBindQuery(&q)
+
+	res, err := h.Order.ListOrders(ctx.Request.Context(), q)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+// UpdateStatus lets admins move an order through its lifecycle.
+func (h *HandlerOrder) UpdateStatus(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.UpdateOrderStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Order.UpdateOrderStatus(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "status updated", res)
+}
+
+// CreateShipment ships some or all remaining items of an order.
+func (h *HandlerOrder) CreateShipment(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.CreateShipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Order.CreateShipment(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "shipment created", res)
+}
+
+func (h *HandlerOrder) UpdateShipmentStatus(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	shipmentID, _ := strconv.ParseUint(ctx.Param("shipment_id"), 10, 64)
+	var req dto.UpdateShipmentStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Order.UpdateShipmentStatus(ctx.Request.Context(), uint(id64), uint(shipmentID), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "shipment updated", res)
+}
+
+// Pay starts the online payment of a created order.
+func (h *HandlerOrder) Pay(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	res, err := h.Order.PayOrder(ctx.Request.Context(), uint(id64), customerID)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "payment started", res)
+}
+
+// Payment returns the payment status, refreshed from the provider.
+func (h *HandlerOrder) Payment(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	res, err := h.Order.GetPayment(ctx.Request.Context(), uint(id64), customerID)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "payment", res)
+}
+
+func (h *HandlerOrder) Refund(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.RefundPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Order.RefundPayment(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "refunded", res)
+}
+
+// CollectCOD records the cash a courier collected on delivery.
+func (h *HandlerOrder) CollectCOD(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.CollectCODRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Order.CollectCOD(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "cash collected", res)
+}
+
+// VerifyPickup hands over the pickup order whose code staff scanned.
+func (h *HandlerOrder) VerifyPickup(ctx *gin.Context) {
+	var req dto.VerifyPickupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Order.VerifyPickup(ctx.Request.Context(), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "picked up", res)
+}
+
+// PaymentWebhook receives charge updates pushed by payment providers.
+func (h *HandlerOrder) PaymentWebhook(ctx *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, 1<<20))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Order.HandlePaymentWebhook(ctx.Request.Context(), ctx.Param("method"), ctx.Request.Header, body); err != nil {
+		h.Logger.Warn("payment webhook not applied", zap.String("method", ctx.Param("method")), zap.Error(err))
+		code := http.StatusBadRequest
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			code = http.StatusUnauthorized
+		}
+		response.ResponseBadRequest(ctx, code, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", nil)
+}
+
+// Export streams orders and order lines for accounting as CSV or XLSX.
+func (h *HandlerOrder) Export(ctx *gin.Context) {
+	var q dto.OrderExportQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.download(ctx, "orders", q.Format, func(w io.Writer) error {
+		return h.Order.ExportOrders(ctx.Request.Context(), q, w)
+	})
+}
+
+// PickList downloads the aggregated pick list for the selected processing orders.
+func (h *HandlerOrder) PickList(ctx *gin.Context) {
+	var q dto.FulfillmentQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if q.Format == "" {
+		q.Format = "pdf"
+	}
+	h.download(ctx, "pick-list", q.Format, func(w io.Writer) error {
+		return h.Order.ExportPickList(ctx.Request.Context(), q, w)
+	})
+}
+
+// PackingSlips downloads one packing slip per selected processing order.
+func (h *HandlerOrder) PackingSlips(ctx *gin.Context) {
+	var q dto.FulfillmentQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if q.Format == "" {
+		q.Format = "pdf"
+	}
+	h.download(ctx, "packing-slips", q.Format, func(w io.Writer) error {
+		return h.Order.ExportPackingSlips(ctx.Request.Context(), q, w)
+	})
+}
+
+var downloadContentTypes = map[string]string{
+	"csv":  "text/csv",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"pdf":  "application/pdf",
+}
+
+// download streams a file written by write as an attachment. Errors raised
+// before anything was written are returned as a normal JSON error.
+func (h *HandlerOrder) download(ctx *gin.Context, name, format string, write func(io.Writer) error) {
+	if format == "" {
+		format = "csv"
+	}
+	if contentType, ok := downloadContentTypes[format]; ok {
+		ctx.Header("Content-Type", contentType)
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().Format("20060102150405"), format))
+	}
+
+	if err := write(ctx.Writer); err != nil {
+		if ctx.Writer.Written() {
+			// headers are gone already; all we can do is log the truncated file
+			h.Logger.Error("download aborted", zap.String("file", name), zap.Error(err))
+			return
+		}
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+	}
+}
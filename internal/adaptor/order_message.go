@@ -0,0 +1,101 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerOrderMessage struct {
+	Message usecase.OrderMessageService
+	Logger  *zap.Logger
+}
+
+func NewHandlerOrderMessage(message usecase.OrderMessageService, logger *zap.Logger) HandlerOrderMessage {
+	return HandlerOrderMessage{Message: message, Logger: logger}
+}
+
+// CustomerList returns the thread of the customer's order without internal notes.
+func (h *HandlerOrderMessage) CustomerList(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	res, err := h.Message.ListForCustomer(ctx.Request.Context(), uint(id64), customerID)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerOrderMessage) CustomerPost(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.CreateOrderMessageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	res, err := h.Message.PostByCustomer(ctx.Request.Context(), uint(id64), customerID, req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerOrderMessage) CustomerMarkRead(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	if err := h.Message.MarkReadByCustomer(ctx.Request.Context(), uint(id64), customerID); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "marked as read", nil)
+}
+
+// AdminList returns the full thread including internal notes.
+func (h *HandlerOrderMessage) AdminList(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	res, err := h.Message.ListForAdmin(ctx.Request.Context(), uint(id64))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerOrderMessage) AdminPost(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.CreateOrderMessageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	uid, _ := ctx.Get("userID")
+	adminID, _ := uid.(uint)
+	r, _ := ctx.Get("userRole")
+	role, _ := r.(string)
+	res, err := h.Message.PostByAdmin(ctx.Request.Context(), uint(id64), adminID, role, req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerOrderMessage) AdminMarkRead(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err := h.Message.MarkReadByAdmin(ctx.Request.Context(), uint(id64)); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "marked as read", nil)
+}
@@ -0,0 +1,63 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HandlerMockPayment stands in for the payment page of a real gateway so
+// charges of the mock provider can be settled locally. Settling a charge
+// delivers the signed webhook like the real gateway would.
+type HandlerMockPayment struct {
+	Mock   *payment.MockProvider
+	Order  usecase.OrderService
+	Logger *zap.Logger
+}
+
+func NewHandlerMockPayment(mock *payment.MockProvider, order usecase.OrderService, logger *zap.Logger) HandlerMockPayment {
+	return HandlerMockPayment{Mock: mock, Order: order, Logger: logger}
+}
+
+func (h *HandlerMockPayment) Get(ctx *gin.Context) {
+	ch, err := h.Mock.GetCharge(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "charge", ch)
+}
+
+func (h *HandlerMockPayment) Complete(ctx *gin.Context) {
+	ch, err := h.Mock.Complete(ctx.Param("id"))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendWebhook(ctx, ch.ID)
+	response.ResponseSuccess(ctx, http.StatusOK, "charge paid", ch)
+}
+
+func (h *HandlerMockPayment) Fail(ctx *gin.Context) {
+	ch, err := h.Mock.Fail(ctx.Param("id"))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendWebhook(ctx, ch.ID)
+	response.ResponseSuccess(ctx, http.StatusOK, "charge failed", ch)
+}
+
+func (h *HandlerMockPayment) sendWebhook(ctx *gin.Context, chargeID string) {
+	header, body, err := h.Mock.Webhook(chargeID)
+	if err == nil {
+		err = h.Order.HandlePaymentWebhook(ctx.Request.Context(), "mock", header, body)
+	}
+	if err != nil {
+		h.Logger.Warn("mock payment webhook failed", zap.String("charge_id", chargeID), zap.Error(err))
+	}
+}
@@ -0,0 +1,78 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerPickup struct {
+	Pickup usecase.PickupService
+	Logger *zap.Logger
+}
+
+func NewHandlerPickup(pickup usecase.PickupService, logger *zap.Logger) HandlerPickup {
+	return HandlerPickup{Pickup: pickup, Logger: logger}
+}
+
+// Locations lists the stores customers can pick up at.
+func (h *HandlerPickup) Locations(ctx *gin.Context) {
+	res, err := h.Pickup.ListLocations(ctx.Request.Context(), false)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerPickup) List(ctx *gin.Context) {
+	res, err := h.Pickup.ListLocations(ctx.Request.Context(), true)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerPickup) Create(ctx *gin.Context) {
+	var req dto.PickupLocationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Pickup.CreateLocation(ctx.Request.Context(), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerPickup) Update(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.PickupLocationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Pickup.UpdateLocation(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+func (h *HandlerPickup) Delete(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err := h.Pickup.DeleteLocation(ctx.Request.Context(), uint(id64)); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "deleted", nil)
+}
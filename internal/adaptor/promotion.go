@@ -0,0 +1,153 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerPromotion struct {
+	Promotion usecase.PromotionService
+	Logger    *zap.Logger
+}
+
+func NewHandlerPromotion(promotion usecase.PromotionService, logger *zap.Logger) HandlerPromotion {
+	return HandlerPromotion{Promotion: promotion, Logger: logger}
+}
+
+func (h *HandlerPromotion) List(ctx *gin.Context) {
+	var q dto.PromotionListQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Promotion.ListPromotions(ctx.Request.Context(), q)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerPromotion) Get(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	res, err := h.Promotion.GetPromotion(ctx.Request.Context(), uint(id64))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerPromotion) Create(ctx *gin.Context) {
+	var req dto.PromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Promotion.CreatePromotion(ctx.Request.Context(), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerPromotion) Update(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.PromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Promotion.UpdatePromotion(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+func (h *HandlerPromotion) Delete(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err := h.Promotion.DeletePromotion(ctx.Request.Context(), uint(id64)); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "deleted", nil)
+}
+
+func (h *HandlerPromotion) TogglePublished(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.PromotionPublishRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Promotion.TogglePublished(ctx.Request.Context(), uint(id64), *req.Published); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", nil)
+}
+
+// AttachProducts limits the promotion to the given products, on top of the
+// ones already attached.
+func (h *HandlerPromotion) AttachProducts(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.PromotionProductsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Promotion.AttachProducts(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+func (h *HandlerPromotion) DetachProduct(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	productID, _ := strconv.ParseUint(ctx.Param("product_id"), 10, 64)
+	res, err := h.Promotion.DetachProduct(ctx.Request.Context(), uint(id64), uint(productID))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+// AttachCategories limits the promotion to products in the given categories,
+// on top of the products and categories already attached.
+func (h *HandlerPromotion) AttachCategories(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.PromotionCategoriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Promotion.AttachCategories(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+func (h *HandlerPromotion) DetachCategory(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	categoryID, _ := strconv.ParseUint(ctx.Param("category_id"), 10, 64)
+	res, err := h.Promotion.DetachCategory(ctx.Request.Context(), uint(id64), uint(categoryID))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
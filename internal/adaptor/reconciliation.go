@@ -0,0 +1,72 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerReconciliation struct {
+	Reconciliation usecase.ReconciliationService
+	Logger         *zap.Logger
+}
+
+func NewHandlerReconciliation(reconciliation usecase.ReconciliationService, logger *zap.Logger) HandlerReconciliation {
+	return HandlerReconciliation{Reconciliation: reconciliation, Logger: logger}
+}
+
+// Import reconciles an uploaded settlement CSV ("file") against our payments.
+func (h *HandlerReconciliation) Import(ctx *gin.Context) {
+	var req dto.ReconcileRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, "file is required")
+		return
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer f.Close()
+
+	uid, _ := ctx.Get("userID")
+	adminID, _ := uid.(uint)
+	res, err := h.Reconciliation.Reconcile(ctx.Request.Context(), adminID, req, fileHeader.Filename, f)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "reconciled", res)
+}
+
+func (h *HandlerReconciliation) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	res, err := h.Reconciliation.ListRuns(ctx.Request.Context(), page, limit)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+// Get returns a run with its mismatches, filtered by ?issue= when given.
+func (h *HandlerReconciliation) Get(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	res, err := h.Reconciliation.GetRun(ctx.Request.Context(), uint(id64), ctx.Query("issue"))
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
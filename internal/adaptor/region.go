@@ -0,0 +1,43 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerRegion struct {
+	Region usecase.RegionService
+	Logger *zap.Logger
+}
+
+func NewHandlerRegion(region usecase.RegionService, logger *zap.Logger) HandlerRegion {
+	return HandlerRegion{Region: region, Logger: logger}
+}
+
+func (h *HandlerRegion) Provinces(ctx *gin.Context) {
+	res, err := h.Region.ListProvinces(ctx.Request.Context())
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+// Cities lists the cities of a province, optionally filtered by name.
+func (h *HandlerRegion) Cities(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var q dto.CityListQuery
+	_ = ctx.ShouldBindQuery(&q)
+	res, err := h.Region.ListCities(ctx.Request.Context(), uint(id64), q)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
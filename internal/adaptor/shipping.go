@@ -0,0 +1,107 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerShipping struct {
+	Shipping usecase.ShippingService
+	Logger   *zap.Logger
+}
+
+func NewHandlerShipping(shipping usecase.ShippingService, logger *zap.Logger) HandlerShipping {
+	return HandlerShipping{Shipping: shipping, Logger: logger}
+}
+
+func (h *HandlerShipping) ListZones(ctx *gin.Context) {
+	res, err := h.Shipping.ListZones(ctx.Request.Context())
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+func (h *HandlerShipping) CreateZone(ctx *gin.Context) {
+	var req dto.ShippingZoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Shipping.CreateZone(ctx.Request.Context(), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerShipping) UpdateZone(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.ShippingZoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Shipping.UpdateZone(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+func (h *HandlerShipping) DeleteZone(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err := h.Shipping.DeleteZone(ctx.Request.Context(), uint(id64)); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "deleted", nil)
+}
+
+func (h *HandlerShipping) CreateMethod(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.ShippingMethodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Shipping.CreateMethod(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
+
+func (h *HandlerShipping) UpdateMethod(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.ShippingMethodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.Shipping.UpdateMethod(ctx.Request.Context(), uint(id64), req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "updated", res)
+}
+
+func (h *HandlerShipping) DeleteMethod(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err := h.Shipping.DeleteMethod(ctx.Request.Context(), uint(id64)); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "deleted", nil)
+}
@@ -89,6 +89,19 @@ func (h *HandlerStock) Delete(ctx *gin.Context) {
 	response.ResponseSuccess(ctx, http.StatusOK, "stock deleted (set 0)", nil)
 }
 
+func (h *HandlerStock) SetWeight(ctx *gin.Context) {
+	var req dto.SetWeightRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Stock.SetWeight(ctx.Request.Context(), req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "weight set", nil)
+}
+
 func (h *HandlerStock) VariantsDropdown(ctx *gin.Context) {
 	var q dto.VariantDropdownQuery
 	_ = ctx.ShouldBindQuery(&q)
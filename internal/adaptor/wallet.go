@@ -0,0 +1,63 @@
+package adaptor
+
+import (
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HandlerWallet struct {
+	Wallet usecase.WalletService
+	Logger *zap.Logger
+}
+
+func NewHandlerWallet(wallet usecase.WalletService, logger *zap.Logger) HandlerWallet {
+	return HandlerWallet{Wallet: wallet, Logger: logger}
+}
+
+// Balance returns the logged in customer's balance and ledger.
+func (h *HandlerWallet) Balance(ctx *gin.Context) {
+	uid, _ := ctx.Get("userID")
+	customerID, _ := uid.(uint)
+	h.wallet(ctx, customerID)
+}
+
+// AdminBalance returns the balance and ledger of any customer.
+func (h *HandlerWallet) AdminBalance(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	h.wallet(ctx, uint(id64))
+}
+
+func (h *HandlerWallet) wallet(ctx *gin.Context, customerID uint) {
+	page, _ := strconv.Atoi(ctx.Query("page"))
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	res, err := h.Wallet.GetWallet(ctx.Request.Context(), customerID, page, limit)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusOK, "ok", res)
+}
+
+// Adjust credits or debits a customer's wallet.
+func (h *HandlerWallet) Adjust(ctx *gin.Context) {
+	id64, _ := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	var req dto.WalletAdjustRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	uid, _ := ctx.Get("userID")
+	adminID, _ := uid.(uint)
+	res, err := h.Wallet.Adjust(ctx.Request.Context(), uint(id64), adminID, req)
+	if err != nil {
+		response.ResponseBadRequest(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.ResponseSuccess(ctx, http.StatusCreated, "created", res)
+}
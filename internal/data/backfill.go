@@ -0,0 +1,73 @@
+package data
+
+import (
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// backfill brings rows written before a schema change up to date. Every step
+// only touches rows it has not handled yet, so it is safe to run on each start.
+func backfill(db *gorm.DB) error {
+	if err := backfillOrderTotals(db); err != nil {
+		return err
+	}
+	return backfillShipments(db)
+}
+
+// backfillOrderTotals fills the subtotal and grand total of orders placed
+// before totals were persisted, from their items and discount. Those orders
+// were charged neither tax nor shipping.
+func backfillOrderTotals(db *gorm.DB) error {
+	return db.Exec(`UPDATE orders SET subtotal = t.subtotal, grand_total = GREATEST(t.subtotal - orders.discount, 0)
+		FROM (SELECT order_id, ROUND(SUM(quantity * unit_price)::numeric, 2) AS subtotal FROM order_items GROUP BY order_id) t
+		WHERE t.order_id = orders.id AND orders.subtotal = 0 AND orders.grand_total = 0 AND t.subtotal > 0`).Error
+}
+
+// backfillShipments gives orders shipped before split shipments existed one
+// shipment covering all their items, carrying over the tracking number that
+// used to live on the order, so they keep their tracking number and can still
+// be delivered.
+func backfillShipments(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&entity.Order{}, "tracking_number") {
+		return nil
+	}
+	var legacy []struct {
+		ID             uint
+		Status         string
+		TrackingNumber *string
+		UpdatedAt      time.Time
+	}
+	err := db.Table("orders").Select("id, status, tracking_number, updated_at").
+		Where("(tracking_number IS NOT NULL AND tracking_number <> '') OR status IN ?", []string{entity.OrderStatusShipped, entity.OrderStatusDelivered}).
+		Where("NOT EXISTS (SELECT 1 FROM shipments WHERE shipments.order_id = orders.id)").
+		Scan(&legacy).Error
+	if err != nil {
+		return err
+	}
+	for _, o := range legacy {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var items []entity.OrderItem
+			if err := tx.Where("order_id = ?", o.ID).Find(&items).Error; err != nil {
+				return err
+			}
+			sh := entity.Shipment{OrderID: o.ID, Status: entity.ShipmentStatusShipped, ShippedAt: o.UpdatedAt}
+			if o.TrackingNumber != nil {
+				sh.TrackingNumber = *o.TrackingNumber
+			}
+			if o.Status == entity.OrderStatusDelivered {
+				sh.Status = entity.ShipmentStatusDelivered
+				sh.DeliveredAt = &o.UpdatedAt
+			}
+			for _, it := range items {
+				sh.Items = append(sh.Items, entity.ShipmentItem{OrderItemID: it.ID, Quantity: it.Quantity})
+			}
+			return tx.Create(&sh).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,7 +6,14 @@ type Address struct {
 	Customer   *Customer `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
 	Fullname   string    `json:"fullname"`
 	Email      string    `json:"email"`
+	Phone      string    `gorm:"size:20" json:"phone"`
 	Address    string    `json:"address"`
+	ProvinceID uint      `json:"province_id"`
+	Province   string    `json:"province"`
+	CityID     uint      `json:"city_id"`
+	City       string    `json:"city"`
+	District   string    `json:"district"`
+	PostalCode string    `gorm:"size:10" json:"postal_code"`
 	IsDefault  bool      `json:"is_default" gorm:"default:false"`
 }
 
@@ -27,7 +34,14 @@ func SeedAddresses() []Address {
 			CustomerID: 4,
 			Fullname:   "Zahra",
 			Email:      "zahra@example.com",
-			Address:    "Jl. Kebangsaan No.10, Bandung",
+			Phone:      "+6281234567890",
+			Address:    "Jl. Kebangsaan No.10",
+			ProvinceID: 32,
+			Province:   "Jawa Barat",
+			CityID:     3273,
+			City:       "Kota Bandung",
+			District:   "Sumur Bandung",
+			PostalCode: "40115",
 			IsDefault:  true,
 		},
 	}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+const (
+	GiftCardRedeem  = "redeem"
+	GiftCardReverse = "reverse"
+)
+
+// GiftCard is a prepaid code that pays for orders until its balance runs out
+// or it expires. Cards generated together share a BatchID.
+type GiftCard struct {
+	Model
+	Code           string     `gorm:"uniqueIndex;size:32" json:"code"`
+	InitialBalance float64    `json:"initial_balance"`
+	Balance        float64    `json:"balance"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	BatchID        string     `gorm:"index;size:32" json:"batch_id"`
+	Note           string     `json:"note"`
+	IssuedBy       string     `json:"issued_by"`
+}
+
+// GiftCardRedemption is one line of a gift card's ledger: a redemption at
+// checkout, or its reversal when the order is cancelled.
+type GiftCardRedemption struct {
+	Model
+	GiftCardID   uint    `gorm:"index" json:"gift_card_id"`
+	OrderID      uint    `gorm:"index" json:"order_id"`
+	Type         string  `json:"type"`
+	Amount       float64 `json:"amount"`
+	BalanceAfter float64 `json:"balance_after"`
+}
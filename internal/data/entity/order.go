@@ -1,19 +1,62 @@
 package entity
 
+import "time"
+
+// Order lifecycle statuses. Partially shipped, shipped and delivered are
+// derived from the order's shipments. Pickup orders go from processing to
+// ready for pickup, and are picked up once staff verify their pickup code.
+const (
+	OrderStatusCreated          = "created"
+	OrderStatusPaid             = "paid"
+	OrderStatusProcessing       = "processing"
+	OrderStatusPartiallyShipped = "partially_shipped"
+	OrderStatusShipped          = "shipped"
+	OrderStatusDelivered        = "delivered"
+	OrderStatusReadyForPickup   = "ready_for_pickup"
+	OrderStatusPickedUp         = "picked_up"
+	OrderStatusCancelled        = "cancelled"
+)
+
+// How an order reaches the customer.
+const (
+	DeliveryModeShipping = "shipping"
+	DeliveryModePickup   = "pickup"
+)
+
 type Order struct {
 	Model
-	CustomerID     uint        `json:"customer_id"`
-	Customer       *Customer   `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
-	AddressID      uint        `json:"address_id"`
-	Address        Address     `gorm:"foreignKey:AddressID" json:"address,omitempty"`
-	Note           string      `json:"note"`
-	PaymentMethod  string      `json:"payment_method"`
-	VoucherCode    *string     `json:"voucher_code"`
-	PromotionID    *uint       `json:"promotion_id,omitempty"`
-	Discount       float64     `json:"discount"`
-	Status         string      `json:"status"`
-	TrackingNumber *string     `json:"tracking_number"`
-	Items          []OrderItem `gorm:"foreignKey:OrderID" json:"items,omitempty"`
+	OrderNumber      *string     `gorm:"uniqueIndex;size:32" json:"order_number"`
+	CustomerID       uint        `json:"customer_id"`
+	Customer         *Customer   `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
+	AddressID        uint        `json:"address_id"`
+	Address          Address     `gorm:"foreignKey:AddressID" json:"address,omitempty"`
+	ShippingName     string      `json:"shipping_name"`
+	ShippingEmail    string      `json:"shipping_email"`
+	ShippingPhone    string      `json:"shipping_phone"`
+	ShippingAddress  string      `json:"shipping_address"`
+	DeliveryMode     string      `gorm:"size:16;default:shipping" json:"delivery_mode"`
+	PickupLocationID *uint       `json:"pickup_location_id,omitempty"`
+	PickupCode       *string     `gorm:"uniqueIndex;size:16" json:"pickup_code,omitempty"`
+	PickedUpAt       *time.Time  `json:"picked_up_at,omitempty"`
+	ShippingMethodID *uint       `json:"shipping_method_id,omitempty"`
+	ShippingMethod   string      `json:"shipping_method"`
+	TotalWeight      int         `json:"total_weight"`
+	Note             string      `json:"note"`
+	PaymentMethod    string      `json:"payment_method"`
+	PaymentExpiresAt *time.Time  `gorm:"index" json:"payment_expires_at"`
+	VoucherCode      *string     `json:"voucher_code"`
+	PromotionID      *uint       `json:"promotion_id,omitempty"`
+	Subtotal         float64     `json:"subtotal"`
+	Discount         float64     `json:"discount"`
+	ShippingFee      float64     `json:"shipping_fee"`
+	Tax              float64     `json:"tax"`
+	GrandTotal       float64     `json:"grand_total"`
+	WalletAmount     float64     `json:"wallet_amount"`
+	GiftCardID       *uint       `json:"gift_card_id,omitempty"`
+	GiftCardAmount   float64     `json:"gift_card_amount"`
+	Status           string      `json:"status"`
+	Items            []OrderItem `gorm:"foreignKey:OrderID" json:"items,omitempty"`
+	Shipments        []Shipment  `gorm:"foreignKey:OrderID" json:"shipments,omitempty"`
 }
 
 type OrderItem struct {
@@ -24,17 +67,33 @@ type OrderItem struct {
 	ProductVariant   ProductVariant `gorm:"foreignKey:ProductVariantID" json:"product_variant,omitempty"`
 	Quantity         int            `json:"quantity"`
 	UnitPrice        float64        `json:"unit_price"`
+	Weight           int            `json:"weight"`
+	ProductName      string         `json:"product_name"`
+	VariantName      string         `json:"variant_name"`
+	SKU              string         `json:"sku"`
+	PhotoURL         string         `json:"photo_url"`
+}
+
+// OrderSequence hands out per-day order number sequences.
+type OrderSequence struct {
+	Day       string `gorm:"primaryKey;size:8" json:"day"`
+	LastValue int    `json:"last_value"`
 }
 
 // SeedOrders returns default orders for seeding
 func SeedOrders() []Order {
 	return []Order{
 		{
-			CustomerID:    1,
-			AddressID:     1,
-			Note:          "Test order",
-			PaymentMethod: "gopay",
-			Status:        "created",
+			CustomerID:      1,
+			AddressID:       1,
+			ShippingName:    "Zahra",
+			ShippingEmail:   "zahra@example.com",
+			ShippingAddress: "Jl. Kebangsaan No.10, Bandung",
+			Note:            "Test order",
+			PaymentMethod:   "gopay",
+			Subtotal:        100.0,
+			GrandTotal:      100.0,
+			Status:          OrderStatusCreated,
 			Items: []OrderItem{
 				{ProductVariantID: 1, Quantity: 1, UnitPrice: 100.0},
 			},
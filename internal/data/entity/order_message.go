@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// OrderMessage is one post on the communication thread of an order. Internal
+// messages are admin-only notes that customers never see.
+type OrderMessage struct {
+	Model
+	OrderID          uint       `gorm:"index" json:"order_id"`
+	Order            *Order     `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+	AuthorID         uint       `json:"author_id"`
+	AuthorRole       string     `json:"author_role"`
+	Body             string     `json:"body"`
+	Internal         bool       `gorm:"default:false" json:"internal"`
+	ReadByCustomerAt *time.Time `json:"read_by_customer_at"`
+	ReadByAdminAt    *time.Time `json:"read_by_admin_at"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Payment is a charge created at a payment provider for an order. Status
+// mirrors the provider's charge status.
+type Payment struct {
+	Model
+	OrderID        uint       `gorm:"index" json:"order_id"`
+	Method         string     `json:"method"`
+	ChargeID       string     `gorm:"uniqueIndex;size:64" json:"charge_id"`
+	Amount         float64    `json:"amount"`
+	RefundedAmount float64    `json:"refunded_amount"`
+	Status         string     `json:"status"`
+	PaymentURL     string     `json:"payment_url"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CollectedBy    string     `json:"collected_by,omitempty"` // courier, for cash on delivery
+	// NeedsRefund marks a charge paid after its order was cancelled
+	NeedsRefund bool `gorm:"index" json:"needs_refund"`
+}
+
+// PaymentEvent is a webhook event that was applied; its id guards against
+// processing redeliveries twice.
+type PaymentEvent struct {
+	Model
+	Method   string `gorm:"uniqueIndex:idx_payment_event;size:32" json:"method"`
+	EventID  string `gorm:"uniqueIndex:idx_payment_event;size:128" json:"event_id"`
+	ChargeID string `json:"charge_id"`
+	Status   string `json:"status"`
+}
+
+// PaymentWebhookLog keeps every raw webhook request, authentic or not, for
+// dispute investigation.
+type PaymentWebhookLog struct {
+	Model
+	Method  string `gorm:"index" json:"method"`
+	EventID string `json:"event_id"`
+	Headers string `gorm:"type:text" json:"headers"`
+	Payload string `gorm:"type:text" json:"payload"`
+	Result  string `json:"result"` // processed, duplicate, rejected or failed
+	Error   string `json:"error"`
+}
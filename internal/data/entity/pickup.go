@@ -0,0 +1,12 @@
+package entity
+
+// PickupLocation is a store where customers can collect click-and-collect
+// orders. Inactive locations stay on old orders but are not offered at checkout.
+type PickupLocation struct {
+	Model
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	Phone        string `json:"phone"`
+	OpeningHours string `json:"opening_hours"`
+	Active       bool   `json:"active" gorm:"default:true"`
+}
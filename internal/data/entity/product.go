@@ -20,6 +20,7 @@ type ProductVariant struct {
 	Product    Product     `gorm:"foreignKey:ProductID" json:"product,omitempty"`
 	Variant    string      `json:"variant"`
 	Stock      int         `json:"stock"`
+	Weight     int         `json:"weight"`
 	CartItems  []CartItem  `gorm:"foreignKey:ProductVariantID" json:"cart_items,omitempty"`
 	OrderItems []OrderItem `gorm:"foreignKey:ProductVariantID" json:"order_items,omitempty"`
 	Wishlists  []Wishlist  `gorm:"foreignKey:ProductVariantID" json:"wishlists,omitempty"`
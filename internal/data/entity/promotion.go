@@ -2,24 +2,57 @@ package entity
 
 import "time"
 
+// Promotion types. Percentage discounts take Discount percent off, fixed ones
+// take Discount off the order and per item ones take Discount off every unit.
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PromotionTypePerItem    = "per_item"
+)
+
+// Customer segments promotions can target, by the number of orders placed
+// before: none, a few, or at least five.
+const (
+	CustomerSegmentNew       = "new"
+	CustomerSegmentReturning = "returning"
+	CustomerSegmentLoyal     = "loyal"
+)
+
+// Promotion is a discount customers redeem with VoucherCode. UsageLimit
+// counts the uses left and goes down with every order. Products and
+// Categories, when set, limit the discount to matching order lines; the
+// other fields are conditions left empty when they do not apply.
 type Promotion struct {
 	Model
-	Name           string             `json:"name"`
-	Type           string             `json:"type"`
-	StartDate      time.Time          `json:"start_date"`
-	EndDate        time.Time          `json:"end_date"`
-	Discount       float64            `json:"discount"`
-	UsageLimit     int                `json:"usage_limit"`
-	VoucherCode    *string            `json:"voucher_code,omitempty"`
-	ShowOnCheckout bool               `json:"show_on_checkout"`
-	Published      bool               `json:"published"`
-	Products       []PromotionProduct `gorm:"foreignKey:PromotionID" json:"products,omitempty"`
+	Name           string              `json:"name"`
+	Type           string              `json:"type"`
+	StartDate      time.Time           `json:"start_date"`
+	EndDate        time.Time           `json:"end_date"`
+	Discount       float64             `json:"discount"`
+	MaxDiscount    float64             `json:"max_discount"` // 0 means no cap
+	MinSubtotal    float64             `json:"min_subtotal"`
+	FirstOrderOnly bool                `json:"first_order_only"`
+	Segment        string              `gorm:"size:16" json:"segment"`
+	PaymentMethods string              `json:"payment_methods"` // comma separated
+	UsageLimit     int                 `json:"usage_limit"`
+	VoucherCode    *string             `gorm:"uniqueIndex;size:32" json:"voucher_code,omitempty"`
+	ShowOnCheckout bool                `json:"show_on_checkout"`
+	Published      bool                `json:"published"`
+	Products       []PromotionProduct  `gorm:"foreignKey:PromotionID" json:"products,omitempty"`
+	Categories     []PromotionCategory `gorm:"foreignKey:PromotionID" json:"categories,omitempty"`
 }
 
 type PromotionProduct struct {
 	Model
-	PromotionID uint      `json:"promotion_id"`
-	ProductID   uint      `json:"product_id"`
+	PromotionID uint      `gorm:"uniqueIndex:idx_promotion_product" json:"promotion_id"`
+	ProductID   uint      `gorm:"uniqueIndex:idx_promotion_product" json:"product_id"`
 	Promotion   Promotion `gorm:"foreignKey:PromotionID" json:"promotion,omitempty"`
 	Product     Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
 }
+
+type PromotionCategory struct {
+	Model
+	PromotionID uint     `gorm:"uniqueIndex:idx_promotion_category" json:"promotion_id"`
+	CategoryID  uint     `gorm:"uniqueIndex:idx_promotion_category" json:"category_id"`
+	Category    Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// Reconciliation issues. Missing in settlement means we recorded money the
+// provider did not settle; missing in records means the opposite.
+const (
+	ReconcileMissingInSettlement = "missing_in_settlement"
+	ReconcileMissingInRecords    = "missing_in_records"
+	ReconcileAmountDiffers       = "amount_differs"
+	ReconcileDuplicate           = "duplicate"
+)
+
+// ReconciliationRun is one comparison of a provider settlement file with the
+// payments recorded for a period.
+type ReconciliationRun struct {
+	Model
+	Method    string               `gorm:"index" json:"method"`
+	FileName  string               `json:"file_name"`
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	Rows      int                  `json:"rows"`
+	Matched   int                  `json:"matched"`
+	Flagged   int                  `json:"flagged"`
+	StartedBy string               `json:"started_by"`
+	Items     []ReconciliationItem `gorm:"foreignKey:RunID" json:"items,omitempty"`
+}
+
+// ReconciliationItem is a mismatch found by a run. Line is the settlement
+// file line it came from, or 0 when the settlement has no such line.
+type ReconciliationItem struct {
+	Model
+	RunID          uint    `gorm:"index" json:"run_id"`
+	Issue          string  `gorm:"index" json:"issue"`
+	Type           string  `json:"type"`
+	ChargeID       string  `json:"charge_id"`
+	OrderID        *uint   `json:"order_id"`
+	Line           int     `json:"line"`
+	RecordedAmount float64 `json:"recorded_amount"`
+	SettledAmount  float64 `json:"settled_amount"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+// Province and City are reference data seeded from the bundled region file.
+// Their ids are the government region codes, so they never change between
+// installs.
+type Province struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name   string `gorm:"uniqueIndex" json:"name"`
+	Cities []City `gorm:"foreignKey:ProvinceID" json:"cities,omitempty"`
+}
+
+type City struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	ProvinceID uint   `gorm:"index" json:"province_id"`
+	Name       string `json:"name"`
+}
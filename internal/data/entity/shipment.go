@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Shipment statuses
+const (
+	ShipmentStatusShipped   = "shipped"
+	ShipmentStatusDelivered = "delivered"
+)
+
+// Shipment is one parcel of an order. An order may be split over several
+// shipments, each covering a subset of its items.
+type Shipment struct {
+	Model
+	OrderID        uint            `gorm:"index" json:"order_id"`
+	TrackingNumber string          `gorm:"size:64" json:"tracking_number"`
+	Carrier        string          `json:"carrier"`
+	Status         string          `json:"status"`
+	TrackingStatus string          `json:"tracking_status"`
+	ShippedAt      time.Time       `json:"shipped_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	Items          []ShipmentItem  `gorm:"foreignKey:ShipmentID" json:"items,omitempty"`
+	Events         []ShipmentEvent `gorm:"foreignKey:ShipmentID" json:"events,omitempty"`
+}
+
+type ShipmentItem struct {
+	Model
+	ShipmentID  uint `gorm:"index" json:"shipment_id"`
+	OrderItemID uint `json:"order_item_id"`
+	Quantity    int  `json:"quantity"`
+}
+
+// ShipmentEvent is a tracking update reported by the shipment's carrier.
+type ShipmentEvent struct {
+	Model
+	ShipmentID  uint      `gorm:"uniqueIndex:idx_shipment_event" json:"shipment_id"`
+	EventID     string    `gorm:"uniqueIndex:idx_shipment_event;size:64" json:"event_id"`
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+// ShippingZone groups the destinations that share shipping methods. A zone
+// covers an address when one of its rules matches it.
+type ShippingZone struct {
+	Model
+	Name    string             `json:"name"`
+	Rules   []ShippingZoneRule `gorm:"foreignKey:ZoneID" json:"rules,omitempty"`
+	Methods []ShippingMethod   `gorm:"foreignKey:ZoneID" json:"methods,omitempty"`
+}
+
+// ShippingZoneRule matches addresses by province, postal code prefix or both.
+// Empty fields match anything, so a rule without either is a catch-all.
+type ShippingZoneRule struct {
+	Model
+	ZoneID       uint   `gorm:"index" json:"zone_id"`
+	Province     string `json:"province"`
+	PostalPrefix string `gorm:"size:10" json:"postal_prefix"`
+}
+
+type ShippingMethod struct {
+	Model
+	ZoneID        uint           `gorm:"index" json:"zone_id"`
+	Name          string         `json:"name"`
+	EstimatedDays string         `json:"estimated_days"`
+	Active        bool           `json:"active" gorm:"default:true"`
+	Rates         []ShippingRate `gorm:"foreignKey:MethodID" json:"rates,omitempty"`
+}
+
+// ShippingRate is one tier of a method. Weights are in grams and inclusive;
+// MaxWeight 0 means no upper bound. MinSubtotal lets stores offer a lower
+// fee from a given order value.
+type ShippingRate struct {
+	Model
+	MethodID    uint    `gorm:"index" json:"method_id"`
+	MinWeight   int     `json:"min_weight"`
+	MaxWeight   int     `json:"max_weight"`
+	MinSubtotal float64 `json:"min_subtotal"`
+	Fee         float64 `json:"fee"`
+}
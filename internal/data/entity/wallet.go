@@ -0,0 +1,28 @@
+package entity
+
+const (
+	WalletEntryCredit = "credit"
+	WalletEntryDebit  = "debit"
+)
+
+// Wallet holds a customer's store credit balance. The balance always equals
+// the sum of the customer's wallet entries.
+type Wallet struct {
+	Model
+	CustomerID uint    `gorm:"uniqueIndex" json:"customer_id"`
+	Balance    float64 `json:"balance"`
+}
+
+// WalletEntry is one line of a customer's wallet ledger. Amount is always
+// positive; Type tells whether it was credited or debited.
+type WalletEntry struct {
+	Model
+	CustomerID   uint    `gorm:"index" json:"customer_id"`
+	Type         string  `json:"type"`
+	Amount       float64 `json:"amount"`
+	BalanceAfter float64 `json:"balance_after"`
+	Reason       string  `json:"reason"`
+	OrderID      *uint   `gorm:"index" json:"order_id"`
+	PaymentID    *uint   `json:"payment_id"`
+	CreatedBy    string  `json:"created_by"`
+}
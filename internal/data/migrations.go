@@ -7,9 +7,11 @@ import (
 )
 
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&entity.User{},
 		&entity.Customer{},
+		&entity.Province{},
+		&entity.City{},
 		&entity.Address{},
 		&entity.Category{},
 		&entity.Product{},
@@ -19,11 +21,35 @@ func AutoMigrate(db *gorm.DB) error {
 		&entity.CartItem{},
 		&entity.Order{},
 		&entity.OrderItem{},
+		&entity.OrderSequence{},
+		&entity.OrderMessage{},
+		&entity.Shipment{},
+		&entity.ShipmentItem{},
+		&entity.ShipmentEvent{},
+		&entity.ShippingZone{},
+		&entity.ShippingZoneRule{},
+		&entity.ShippingMethod{},
+		&entity.ShippingRate{},
+		&entity.PickupLocation{},
+		&entity.Payment{},
+		&entity.PaymentEvent{},
+		&entity.PaymentWebhookLog{},
+		&entity.ReconciliationRun{},
+		&entity.ReconciliationItem{},
+		&entity.Wallet{},
+		&entity.WalletEntry{},
+		&entity.GiftCard{},
+		&entity.GiftCardRedemption{},
 		&entity.Wishlist{},
 		&entity.Rating{},
 		&entity.Promotion{},
 		&entity.PromotionProduct{},
+		&entity.PromotionCategory{},
 		&entity.Banner{},
 		&entity.AuthOTP{},
 	)
+	if err != nil {
+		return err
+	}
+	return backfill(db)
 }
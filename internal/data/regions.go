@@ -0,0 +1,35 @@
+package data
+
+import (
+	_ "embed"
+	"encoding/json"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+// regionsFile lists provinces with their cities. Add cities here; they are
+// inserted on the next start.
+//
+//go:embed regions.json
+var regionsFile []byte
+
+// regionSeeds returns the provinces and cities of the bundled region file.
+func regionSeeds() ([]entity.Province, []entity.City, error) {
+	var file []struct {
+		ID     uint          `json:"id"`
+		Name   string        `json:"name"`
+		Cities []entity.City `json:"cities"`
+	}
+	if err := json.Unmarshal(regionsFile, &file); err != nil {
+		return nil, nil, err
+	}
+	provinces := make([]entity.Province, 0, len(file))
+	var cities []entity.City
+	for _, p := range file {
+		provinces = append(provinces, entity.Province{ID: p.ID, Name: p.Name})
+		for _, c := range p.Cities {
+			c.ProvinceID = p.ID
+			cities = append(cities, c)
+		}
+	}
+	return provinces, cities, nil
+}
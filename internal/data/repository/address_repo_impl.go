@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
 )
 
 type addressRepo struct {
@@ -24,13 +26,37 @@ func (r *addressRepo) UpdateAddress(ctx context.Context, addr *entity.Address) e
 	return r.db.WithContext(ctx).Save(addr).Error
 }
 
+// DeleteAddress marks the address deleted rather than removing the row,
+// which orders placed to it still reference. When it was the customer's
+// default, the oldest remaining address becomes the new default.
 func (r *addressRepo) DeleteAddress(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entity.Address{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var addr entity.Address
+		if err := tx.Where("deleted_at IS NULL").First(&addr, id).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&addr).Updates(map[string]any{"deleted_at": time.Now(), "is_default": false}).Error
+		if err != nil {
+			return err
+		}
+		if !addr.IsDefault {
+			return nil
+		}
+		var next entity.Address
+		err = tx.Where("customer_id = ? AND deleted_at IS NULL", addr.CustomerID).Order("created_at, id").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
 }
 
 func (r *addressRepo) GetAddressByID(ctx context.Context, id uint) (*entity.Address, error) {
 	var a entity.Address
-	if err := r.db.WithContext(ctx).First(&a, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").First(&a, id).Error; err != nil {
 		return nil, err
 	}
 	return &a, nil
@@ -38,7 +64,7 @@ func (r *addressRepo) GetAddressByID(ctx context.Context, id uint) (*entity.Addr
 
 func (r *addressRepo) ListAddressesByCustomer(ctx context.Context, customerID uint) ([]entity.Address, error) {
 	var addrs []entity.Address
-	if err := r.db.WithContext(ctx).Where("customer_id = ?", customerID).Find(&addrs).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("customer_id = ? AND deleted_at IS NULL", customerID).Order("created_at, id").Find(&addrs).Error; err != nil {
 		return nil, err
 	}
 	return addrs, nil
@@ -54,7 +80,7 @@ func (r *addressRepo) SetDefaultAddress(ctx context.Context, customerID uint, ad
 
 	// Ensure the address belongs to the customer
 	var addr entity.Address
-	if err := tx.Where("id = ? AND customer_id = ?", addressID, customerID).First(&addr).Error; err != nil {
+	if err := tx.Where("id = ? AND customer_id = ? AND deleted_at IS NULL", addressID, customerID).First(&addr).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
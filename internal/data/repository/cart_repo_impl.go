@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
@@ -18,7 +19,9 @@ func NewCartRepository(db *gorm.DB, log *zap.Logger) CartRepository {
 
 func (r *cartRepo) GetCartByCustomer(ctx context.Context, customerID uint) (*entity.Cart, error) {
 	var cart entity.Cart
-	if err := r.db.WithContext(ctx).Preload("Items").Where("customer_id = ?", customerID).First(&cart).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Preload("Items.ProductVariant.Product.Photos").
+		Where("customer_id = ?", customerID).First(&cart).Error; err != nil {
 		return nil, err
 	}
 	return &cart, nil
@@ -31,3 +34,30 @@ func (r *cartRepo) ClearCart(ctx context.Context, customerID uint) error {
 	}
 	return nil
 }
+
+// AddItems puts the items into the customer's cart in one transaction,
+// creating the cart if needed and merging each with an existing line.
+func (r *cartRepo) AddItems(ctx context.Context, customerID uint, items []entity.CartItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cart entity.Cart
+		if err := tx.Where(entity.Cart{CustomerID: customerID}).FirstOrCreate(&cart).Error; err != nil {
+			return err
+		}
+		for _, it := range items {
+			var item entity.CartItem
+			err := tx.Where("cart_id = ? AND product_variant_id = ?", cart.ID, it.ProductVariantID).First(&item).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = tx.Create(&entity.CartItem{CartID: cart.ID, ProductVariantID: it.ProductVariantID, Quantity: it.Quantity, UnitPrice: it.UnitPrice}).Error
+			} else if err == nil {
+				err = tx.Model(&item).Updates(map[string]any{
+					"quantity":   item.Quantity + it.Quantity,
+					"unit_price": it.UnitPrice,
+				}).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
+)
+
+type giftCardRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewGiftCardRepository(db *gorm.DB, log *zap.Logger) GiftCardRepository {
+	return &giftCardRepo{db: db, log: log}
+}
+
+// CreateGiftCards inserts all cards or none of them.
+func (r *giftCardRepo) CreateGiftCards(ctx context.Context, cards []entity.GiftCard) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&cards, 100).Error
+	})
+}
+
+func (r *giftCardRepo) GetGiftCardByID(ctx context.Context, id uint) (*entity.GiftCard, error) {
+	var g entity.GiftCard
+	if err := r.db.WithContext(ctx).First(&g, id).Error; err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *giftCardRepo) GetGiftCardByCode(ctx context.Context, code string) (*entity.GiftCard, error) {
+	var g entity.GiftCard
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&g).Error; err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *giftCardRepo) ListGiftCards(ctx context.Context, page, limit int, batchID string) ([]entity.GiftCard, int64, error) {
+	var cards []entity.GiftCard
+	var total int64
+	q := r.db.WithContext(ctx).Model(&entity.GiftCard{})
+	if batchID != "" {
+		q = q.Where("batch_id = ?", batchID)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("id DESC").Limit(limit).Offset((page - 1) * limit).Find(&cards).Error; err != nil {
+		return nil, 0, err
+	}
+	return cards, total, nil
+}
+
+func (r *giftCardRepo) ListRedemptions(ctx context.Context, giftCardID uint) ([]entity.GiftCardRedemption, error) {
+	var rs []entity.GiftCardRedemption
+	if err := r.db.WithContext(ctx).Where("gift_card_id = ?", giftCardID).Order("id ASC").Find(&rs).Error; err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// redeemGiftCard takes amount from an unexpired gift card for orderID and
+// writes the redemption. The conditional update keeps concurrent checkouts
+// from spending the same balance twice. tx must be a transaction.
+func redeemGiftCard(tx *gorm.DB, giftCardID, orderID uint, amount float64) error {
+	var balances []float64
+	err := tx.Raw(
+		`UPDATE gift_cards SET balance = balance - ?, updated_at = NOW()
+		 WHERE id = ? AND balance >= ? AND (expires_at IS NULL OR expires_at > ?)
+		 RETURNING balance`, amount, giftCardID, amount, time.Now()).Scan(&balances).Error
+	if err != nil {
+		return err
+	}
+	if len(balances) == 0 {
+		return errors.New("gift card balance is no longer available")
+	}
+	return tx.Create(&entity.GiftCardRedemption{
+		GiftCardID:   giftCardID,
+		OrderID:      orderID,
+		Type:         entity.GiftCardRedeem,
+		Amount:       amount,
+		BalanceAfter: balances[0],
+	}).Error
+}
+
+// reverseGiftCard gives amount redeemed for orderID back to the gift card.
+// tx must be a transaction.
+func reverseGiftCard(tx *gorm.DB, giftCardID, orderID uint, amount float64) error {
+	var balance float64
+	err := tx.Raw(
+		`UPDATE gift_cards SET balance = balance + ?, updated_at = NOW()
+		 WHERE id = ? RETURNING balance`, amount, giftCardID).Scan(&balance).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&entity.GiftCardRedemption{
+		GiftCardID:   giftCardID,
+		OrderID:      orderID,
+		Type:         entity.GiftCardReverse,
+		Amount:       amount,
+		BalanceAfter: balance,
+	}).Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
+)
+
+type orderMessageRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewOrderMessageRepository(db *gorm.DB, log *zap.Logger) OrderMessageRepository {
+	return &orderMessageRepo{db: db, log: log}
+}
+
+func (r *orderMessageRepo) CreateMessage(ctx context.Context, m *entity.OrderMessage) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *orderMessageRepo) ListMessages(ctx context.Context, orderID uint, includeInternal bool) ([]entity.OrderMessage, error) {
+	var msgs []entity.OrderMessage
+	q := r.db.WithContext(ctx).Where("order_id = ?", orderID)
+	if !includeInternal {
+		q = q.Where("internal = ?", false)
+	}
+	if err := q.Order("id ASC").Find(&msgs).Error; err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// MarkReadByCustomer marks every visible support message on the order as read by the customer.
+func (r *orderMessageRepo) MarkReadByCustomer(ctx context.Context, orderID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OrderMessage{}).
+		Where("order_id = ? AND author_role <> ? AND internal = ? AND read_by_customer_at IS NULL", orderID, "customer", false).
+		Update("read_by_customer_at", at).Error
+}
+
+// MarkReadByAdmin marks every customer message on the order as read by support.
+func (r *orderMessageRepo) MarkReadByAdmin(ctx context.Context, orderID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OrderMessage{}).
+		Where("order_id = ? AND author_role = ? AND read_by_admin_at IS NULL", orderID, "customer").
+		Update("read_by_admin_at", at).Error
+}
@@ -2,11 +2,35 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
 )
 
+// OrderExportRow is one order line joined with its order, for accounting exports.
+type OrderExportRow struct {
+	OrderID       uint      `json:"order_id"`
+	OrderNumber   *string   `json:"order_number"`
+	CreatedAt     time.Time `json:"created_at"`
+	Status        string    `json:"status"`
+	CustomerID    uint      `json:"customer_id"`
+	PaymentMethod string    `json:"payment_method"`
+	VoucherCode   *string   `json:"voucher_code"`
+	PromotionID   *uint     `json:"promotion_id"`
+	Subtotal      float64   `json:"subtotal"`
+	Discount      float64   `json:"discount"`
+	ShippingFee   float64   `json:"shipping_fee"`
+	Tax           float64   `json:"tax"`
+	GrandTotal    float64   `json:"grand_total"`
+	SKU           string    `json:"sku"`
+	ProductName   string    `json:"product_name"`
+	VariantName   string    `json:"variant_name"`
+	Quantity      int       `json:"quantity"`
+	UnitPrice     float64   `json:"unit_price"`
+}
+
 type orderRepo struct {
 	db  *gorm.DB
 	log *zap.Logger
@@ -16,18 +40,60 @@ func NewOrderRepository(db *gorm.DB, log *zap.Logger) OrderRepository {
 	return &orderRepo{db: db, log: log}
 }
 
+// CreateOrder reserves stock for every item, creates the order and takes the
+// wallet and gift card amounts in one transaction, so a concurrent checkout
+// can never oversell a variant and a failed checkout never keeps the
+// customer's credit.
 func (r *orderRepo) CreateOrder(ctx context.Context, order *entity.Order) error {
-	return r.db.WithContext(ctx).Create(order).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, it := range order.Items {
+			res := tx.Model(&entity.ProductVariant{}).
+				Where("id = ? AND stock >= ?", it.ProductVariantID, it.Quantity).
+				UpdateColumn("stock", gorm.Expr("stock - ?", it.Quantity))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return errors.New("insufficient stock")
+			}
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if order.GiftCardID != nil && order.GiftCardAmount > 0 {
+			if err := redeemGiftCard(tx, *order.GiftCardID, order.ID, order.GiftCardAmount); err != nil {
+				return err
+			}
+		}
+		if order.WalletAmount <= 0 {
+			return nil
+		}
+		return debitWallet(tx, &entity.WalletEntry{
+			CustomerID: order.CustomerID,
+			Amount:     order.WalletAmount,
+			Reason:     "order payment",
+			OrderID:    &order.ID,
+		})
+	})
 }
 
 func (r *orderRepo) GetOrderByID(ctx context.Context, id uint) (*entity.Order, error) {
 	var o entity.Order
-	if err := r.db.WithContext(ctx).Preload("Items").First(&o, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Items").Preload("Shipments.Items").Preload("Shipments.Events", eventsInOrder).First(&o, id).Error; err != nil {
 		return nil, err
 	}
 	return &o, nil
 }
 
+// GetOrdersByIDs loads the given orders with their items, oldest first.
+func (r *orderRepo) GetOrdersByIDs(ctx context.Context, ids []uint) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.WithContext(ctx).Preload("Items").Where("id IN ?", ids).Order("id ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
 func (r *orderRepo) ListOrdersByCustomer(ctx context.Context, customerID uint, limit, offset int) ([]entity.Order, int64, error) {
 	var orders []entity.Order
 	var total int64
@@ -38,3 +104,174 @@ func (r *orderRepo) ListOrdersByCustomer(ctx context.Context, customerID uint, l
 	}
 	return orders, total, nil
 }
+
+func (r *orderRepo) GetOrderByNumber(ctx context.Context, number string) (*entity.Order, error) {
+	var o entity.Order
+	if err := r.db.WithContext(ctx).Preload("Items").Preload("Shipments.Items").Preload("Shipments.Events", eventsInOrder).Where("order_number = ?", number).First(&o).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *orderRepo) GetOrderByPickupCode(ctx context.Context, code string) (*entity.Order, error) {
+	var o entity.Order
+	if err := r.db.WithContext(ctx).Preload("Items").Where("pickup_code = ?", code).First(&o).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *orderRepo) MarkPickedUp(ctx context.Context, id uint, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("id = ? AND status = ?", id, entity.OrderStatusReadyForPickup).
+		Updates(map[string]interface{}{"status": entity.OrderStatusPickedUp, "picked_up_at": at})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *orderRepo) ListOrders(ctx context.Context, page, limit int, search, status string) ([]entity.Order, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	var orders []entity.Order
+	var total int64
+
+	q := r.db.WithContext(ctx).Model(&entity.Order{})
+	if search != "" {
+		q = q.Where("LOWER(order_number) LIKE LOWER(?) OR LOWER(shipping_name) LIKE LOWER(?)", "%"+search+"%", "%"+search+"%")
+	}
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Preload("Items").Order("id DESC").Limit(limit).Offset((page - 1) * limit).Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// NextOrderSequence atomically increments and returns the order sequence for
+// day (YYYYMMDD); the upsert keeps concurrent checkouts from sharing a number.
+func (r *orderRepo) NextOrderSequence(ctx context.Context, day string) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).Raw(
+		`INSERT INTO order_sequences (day, last_value) VALUES (?, 1)
+		 ON CONFLICT (day) DO UPDATE SET last_value = order_sequences.last_value + 1
+		 RETURNING last_value`, day).Scan(&next).Error
+	return next, err
+}
+
+func (r *orderRepo) UpdateOrderStatus(ctx context.Context, id uint, status string) error {
+	return r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
+
+// StreamOrderLines calls fn for every order line created in [from, to),
+// reading rows one at a time so large ranges are never loaded at once.
+func (r *orderRepo) StreamOrderLines(ctx context.Context, from, to time.Time, status string, fn func(OrderExportRow) error) error {
+	q := r.db.WithContext(ctx).
+		Table("orders o").
+		Select(`o.id as order_id, o.order_number, o.created_at, o.status, o.customer_id,
+		        o.payment_method, o.voucher_code, o.promotion_id,
+		        o.subtotal, o.discount, o.shipping_fee, o.tax, o.grand_total,
+		        oi.sku, oi.product_name, oi.variant_name, oi.quantity, oi.unit_price`).
+		Joins("JOIN order_items oi ON oi.order_id = o.id").
+		Where("o.created_at >= ? AND o.created_at < ?", from, to)
+	if status != "" {
+		q = q.Where("o.status = ?", status)
+	}
+
+	rows, err := q.Order("o.id ASC, oi.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row OrderExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *orderRepo) CountCustomerOrders(ctx context.Context, customerID uint, paymentMethod, status string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("customer_id = ? AND payment_method = ? AND status = ?", customerID, paymentMethod, status).
+		Count(&n).Error
+	return n, err
+}
+
+func (r *orderRepo) CountPlacedOrders(ctx context.Context, customerID uint) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("customer_id = ? AND status <> ?", customerID, entity.OrderStatusCancelled).
+		Count(&n).Error
+	return n, err
+}
+
+// ListExpiredUnpaidOrders returns created orders whose payment deadline passed before now.
+func (r *orderRepo) ListExpiredUnpaidOrders(ctx context.Context, now time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := r.db.WithContext(ctx).Preload("Items").
+		Where("status = ? AND payment_expires_at < ?", entity.OrderStatusCreated, now).
+		Order("payment_expires_at ASC").Limit(limit).Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *orderRepo) CancelOrder(ctx context.Context, o *entity.Order) (bool, error) {
+	cancelled := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// only the caller that moves the order out of its status restocks it
+		res := tx.Model(&entity.Order{}).Where("id = ? AND status = ?", o.ID, o.Status).Update("status", entity.OrderStatusCancelled)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		for _, it := range o.Items {
+			err := tx.Model(&entity.ProductVariant{}).Where("id = ?", it.ProductVariantID).
+				UpdateColumn("stock", gorm.Expr("stock + ?", it.Quantity)).Error
+			if err != nil {
+				return err
+			}
+		}
+		if o.PromotionID != nil {
+			err := tx.Model(&entity.Promotion{}).Where("id = ?", *o.PromotionID).
+				UpdateColumn("usage_limit", gorm.Expr("usage_limit + 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		if o.GiftCardID != nil && o.GiftCardAmount > 0 {
+			if err := reverseGiftCard(tx, *o.GiftCardID, o.ID, o.GiftCardAmount); err != nil {
+				return err
+			}
+		}
+		if o.WalletAmount > 0 {
+			err := creditWallet(tx, &entity.WalletEntry{
+				CustomerID: o.CustomerID,
+				Amount:     o.WalletAmount,
+				Reason:     "order cancelled",
+				OrderID:    &o.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		cancelled = true
+		return nil
+	})
+	return cancelled, err
+}
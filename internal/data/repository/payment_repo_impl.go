@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
+)
+
+type paymentRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewPaymentRepository(db *gorm.DB, log *zap.Logger) PaymentRepository {
+	return &paymentRepo{db: db, log: log}
+}
+
+func (r *paymentRepo) CreatePayment(ctx context.Context, p *entity.Payment) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r *paymentRepo) GetPaymentByChargeID(ctx context.Context, chargeID string) (*entity.Payment, error) {
+	var p entity.Payment
+	if err := r.db.WithContext(ctx).Where("charge_id = ?", chargeID).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *paymentRepo) GetLatestPaymentByOrder(ctx context.Context, orderID uint) (*entity.Payment, error) {
+	var p entity.Payment
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id DESC").First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *paymentRepo) UpdatePayment(ctx context.Context, p *entity.Payment, orderStatus string) (bool, error) {
+	moved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Payment{}).Where("id = ?", p.ID).Updates(map[string]any{
+			"status":          p.Status,
+			"refunded_amount": p.RefundedAmount,
+			"paid_at":         p.PaidAt,
+			"needs_refund":    p.NeedsRefund,
+		}).Error
+		if err != nil || orderStatus == "" {
+			return err
+		}
+		// the order may have been cancelled while the charge was being applied
+		res := tx.Model(&entity.Order{}).Where("id = ? AND status = ?", p.OrderID, entity.OrderStatusCreated).Update("status", orderStatus)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			moved = true
+			return nil
+		}
+		r.log.Warn("payment applied to an order that is no longer created, flagged for refund",
+			zap.Uint("order_id", p.OrderID), zap.Uint("payment_id", p.ID), zap.String("charge_id", p.ChargeID))
+		p.NeedsRefund = true
+		return tx.Model(&entity.Payment{}).Where("id = ?", p.ID).Update("needs_refund", true).Error
+	})
+	return moved, err
+}
+
+func (r *paymentRepo) RefundToWallet(ctx context.Context, p *entity.Payment, e *entity.WalletEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Payment{}).Where("id = ?", p.ID).Updates(map[string]any{
+			"status":          p.Status,
+			"refunded_amount": p.RefundedAmount,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return creditWallet(tx, e)
+	})
+}
+
+func (r *paymentRepo) RecordEvent(ctx context.Context, ev *entity.PaymentEvent) (bool, error) {
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(ev)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *paymentRepo) DeleteEvent(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&entity.PaymentEvent{}, id).Error
+}
+
+func (r *paymentRepo) CreateWebhookLog(ctx context.Context, l *entity.PaymentWebhookLog) error {
+	return r.db.WithContext(ctx).Create(l).Error
+}
+
+func (r *paymentRepo) ListSettledPayments(ctx context.Context, method string, from, to time.Time) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.WithContext(ctx).
+		Where("method = ? AND paid_at >= ? AND paid_at < ?", method, from, to).
+		Order("paid_at ASC").Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+type pickupRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewPickupRepository(db *gorm.DB, log *zap.Logger) PickupRepository {
+	return &pickupRepo{db: db, log: log}
+}
+
+func (r *pickupRepo) ListLocations(ctx context.Context, all bool) ([]entity.PickupLocation, error) {
+	var locations []entity.PickupLocation
+	q := r.db.WithContext(ctx).Order("name ASC, id ASC")
+	if !all {
+		q = q.Where("active = ?", true)
+	}
+	if err := q.Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (r *pickupRepo) GetLocation(ctx context.Context, id uint) (*entity.PickupLocation, error) {
+	var l entity.PickupLocation
+	if err := r.db.WithContext(ctx).First(&l, id).Error; err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *pickupRepo) SaveLocation(ctx context.Context, l *entity.PickupLocation) error {
+	// Select("*") so Active=false is written on update
+	return r.db.WithContext(ctx).Select("*").Save(l).Error
+}
+
+func (r *pickupRepo) DeleteLocation(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.PickupLocation{}, id).Error
+}
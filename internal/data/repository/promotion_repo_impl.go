@@ -18,9 +18,13 @@ func NewPromotionRepository(db *gorm.DB, log *zap.Logger) PromotionRepository {
 	return &promotionRepo{db: db, log: log}
 }
 
+// GetByVoucherCode ignores case, so codes inserted before they were stored
+// upper case can still be redeemed.
 func (r *promotionRepo) GetByVoucherCode(ctx context.Context, code string) (*entity.Promotion, error) {
 	var p entity.Promotion
-	if err := r.db.WithContext(ctx).Where("voucher_code = ?", code).First(&p).Error; err != nil {
+	err := r.db.WithContext(ctx).Preload("Products").Preload("Categories").
+		Where("UPPER(voucher_code) = UPPER(?)", code).First(&p).Error
+	if err != nil {
 		return nil, err
 	}
 	return &p, nil
@@ -38,3 +42,140 @@ func (r *promotionRepo) DecrementUsage(ctx context.Context, id uint) error {
 	r.db.WithContext(ctx).Model(&entity.Promotion{}).Where("id = ?", id).UpdateColumn("updated_at", clause.Expr{SQL: "NOW()"})
 	return nil
 }
+
+// RestoreUsage gives back a use taken by DecrementUsage, e.g. when the order
+// it was taken for could not be created.
+func (r *promotionRepo) RestoreUsage(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&entity.Promotion{}).Where("id = ?", id).
+		UpdateColumn("usage_limit", gorm.Expr("usage_limit + 1")).Error
+}
+
+func (r *promotionRepo) ListPromotions(ctx context.Context, page, limit int, search string) ([]entity.Promotion, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var promos []entity.Promotion
+	q := r.db.WithContext(ctx).Model(&entity.Promotion{})
+	if search != "" {
+		q = q.Where("LOWER(name) LIKE LOWER(?) OR LOWER(voucher_code) LIKE LOWER(?)", "%"+search+"%", "%"+search+"%")
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("id DESC").Limit(limit).Offset((page - 1) * limit).Find(&promos).Error; err != nil {
+		return nil, 0, err
+	}
+	return promos, total, nil
+}
+
+func (r *promotionRepo) GetPromotionByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	var p entity.Promotion
+	err := r.db.WithContext(ctx).
+		Preload("Products", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Products.Product").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Categories.Category").
+		First(&p, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *promotionRepo) CreatePromotion(ctx context.Context, p *entity.Promotion) error {
+	return r.db.WithContext(ctx).Omit("Products", "Categories").Create(p).Error
+}
+
+func (r *promotionRepo) UpdatePromotion(ctx context.Context, p *entity.Promotion) error {
+	// Select("*") so ShowOnCheckout=false and a cleared voucher code are written
+	return r.db.WithContext(ctx).Select("*").Omit("Products", "Categories", "CreatedAt").Save(p).Error
+}
+
+func (r *promotionRepo) DeletePromotion(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("promotion_id = ?", id).Delete(&entity.PromotionProduct{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("promotion_id = ?", id).Delete(&entity.PromotionCategory{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Promotion{}, id).Error
+	})
+}
+
+func (r *promotionRepo) TogglePublished(ctx context.Context, id uint, published bool) error {
+	return r.db.WithContext(ctx).Model(&entity.Promotion{}).
+		Where("id = ?", id).
+		Update("published", published).Error
+}
+
+func (r *promotionRepo) IsVoucherCodeExists(ctx context.Context, code string, excludeID uint) (bool, error) {
+	q := r.db.WithContext(ctx).Model(&entity.Promotion{}).Where("UPPER(voucher_code) = UPPER(?)", code)
+	if excludeID > 0 {
+		q = q.Where("id <> ?", excludeID)
+	}
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *promotionRepo) ExistingProductIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var found []uint
+	if err := r.db.WithContext(ctx).Model(&entity.Product{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func (r *promotionRepo) AttachProducts(ctx context.Context, promotionID uint, productIDs []uint) error {
+	rows := make([]entity.PromotionProduct, 0, len(productIDs))
+	for _, id := range productIDs {
+		rows = append(rows, entity.PromotionProduct{PromotionID: promotionID, ProductID: id})
+	}
+	return r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *promotionRepo) DetachProduct(ctx context.Context, promotionID, productID uint) error {
+	res := r.db.WithContext(ctx).Where("promotion_id = ? AND product_id = ?", promotionID, productID).Delete(&entity.PromotionProduct{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("product is not attached to this promotion")
+	}
+	return nil
+}
+
+func (r *promotionRepo) ExistingCategoryIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var found []uint
+	if err := r.db.WithContext(ctx).Model(&entity.Category{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func (r *promotionRepo) AttachCategories(ctx context.Context, promotionID uint, categoryIDs []uint) error {
+	rows := make([]entity.PromotionCategory, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		rows = append(rows, entity.PromotionCategory{PromotionID: promotionID, CategoryID: id})
+	}
+	return r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *promotionRepo) DetachCategory(ctx context.Context, promotionID, categoryID uint) error {
+	res := r.db.WithContext(ctx).Where("promotion_id = ? AND category_id = ?", promotionID, categoryID).Delete(&entity.PromotionCategory{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("category is not attached to this promotion")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+type reconciliationRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewReconciliationRepository(db *gorm.DB, log *zap.Logger) ReconciliationRepository {
+	return &reconciliationRepo{db: db, log: log}
+}
+
+func (r *reconciliationRepo) CreateRun(ctx context.Context, run *entity.ReconciliationRun) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Items").Create(run).Error; err != nil {
+			return err
+		}
+		if len(run.Items) == 0 {
+			return nil
+		}
+		for i := range run.Items {
+			run.Items[i].RunID = run.ID
+		}
+		return tx.CreateInBatches(&run.Items, 500).Error
+	})
+}
+
+func (r *reconciliationRepo) ListRuns(ctx context.Context, page, limit int) ([]entity.ReconciliationRun, int64, error) {
+	var runs []entity.ReconciliationRun
+	var total int64
+	q := r.db.WithContext(ctx).Model(&entity.ReconciliationRun{})
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("id DESC").Limit(limit).Offset((page - 1) * limit).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+func (r *reconciliationRepo) GetRun(ctx context.Context, id uint) (*entity.ReconciliationRun, error) {
+	var run entity.ReconciliationRun
+	if err := r.db.WithContext(ctx).First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *reconciliationRepo) ListItems(ctx context.Context, runID uint, issue string) ([]entity.ReconciliationItem, error) {
+	var items []entity.ReconciliationItem
+	q := r.db.WithContext(ctx).Where("run_id = ?", runID)
+	if issue != "" {
+		q = q.Where("issue = ?", issue)
+	}
+	if err := q.Order("id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
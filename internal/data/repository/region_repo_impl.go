@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+type regionRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRegionRepository(db *gorm.DB, log *zap.Logger) RegionRepository {
+	return &regionRepo{db: db, log: log}
+}
+
+func (r *regionRepo) ListProvinces(ctx context.Context) ([]entity.Province, error) {
+	var provinces []entity.Province
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&provinces).Error; err != nil {
+		return nil, err
+	}
+	return provinces, nil
+}
+
+func (r *regionRepo) GetProvince(ctx context.Context, id uint) (*entity.Province, error) {
+	var p entity.Province
+	if err := r.db.WithContext(ctx).First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *regionRepo) ListCities(ctx context.Context, provinceID uint, search string) ([]entity.City, error) {
+	var cities []entity.City
+	q := r.db.WithContext(ctx).Where("province_id = ?", provinceID)
+	if search != "" {
+		q = q.Where("LOWER(name) LIKE LOWER(?)", "%"+search+"%")
+	}
+	if err := q.Order("name ASC").Find(&cities).Error; err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+func (r *regionRepo) GetCity(ctx context.Context, id uint) (*entity.City, error) {
+	var c entity.City
+	if err := r.db.WithContext(ctx).First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -3,65 +3,226 @@ package repository
 import (
 	"context"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type Repository struct {
-	RedisRepo     RedisRepository
-	AuthRepo      AuthRepository
-	CustomerRepo  CustomerRepository
-	StockRepo     StockRepository
-	CategoryRepo  CategoryRepository
-	BannerRepo    BannerRepository
-	OrderRepo     OrderRepository
-	AddressRepo   AddressRepository
-	CartRepo      CartRepository
-	PromotionRepo PromotionRepository
-	UserRepo      UserRepository
+	RedisRepo        RedisRepository
+	AuthRepo         AuthRepository
+	CustomerRepo     CustomerRepository
+	StockRepo        StockRepository
+	CategoryRepo     CategoryRepository
+	BannerRepo       BannerRepository
+	OrderRepo        OrderRepository
+	AddressRepo      AddressRepository
+	CartRepo         CartRepository
+	PromotionRepo    PromotionRepository
+	UserRepo         UserRepository
+	OrderMessageRepo OrderMessageRepository
+	ShipmentRepo     ShipmentRepository
+	PaymentRepo      PaymentRepository
+	WalletRepo       WalletRepository
+	GiftCardRepo     GiftCardRepository
+	ReconcileRepo    ReconciliationRepository
+	ShippingRepo     ShippingRepository
+	RegionRepo       RegionRepository
+	PickupRepo       PickupRepository
 }
 
 func NewRepository(db *gorm.DB, log *zap.Logger) Repository {
 	return Repository{
-		RedisRepo:     NewRedisRepository(db, log),
-		AuthRepo:      NewAuthRepository(db, log),
-		CustomerRepo:  NewCustomerRepository(db, log),
-		StockRepo:     NewStockRepository(db, log),
-		CategoryRepo:  NewCategoryRepository(db, log),
-		BannerRepo:    NewBannerRepository(db, log),
-		OrderRepo:     NewOrderRepository(db, log),
-		AddressRepo:   NewAddressRepository(db, log),
-		CartRepo:      NewCartRepository(db, log),
-		PromotionRepo: NewPromotionRepository(db, log),
-		UserRepo:      NewUserRepository(db, log),
+		RedisRepo:        NewRedisRepository(db, log),
+		AuthRepo:         NewAuthRepository(db, log),
+		CustomerRepo:     NewCustomerRepository(db, log),
+		StockRepo:        NewStockRepository(db, log),
+		CategoryRepo:     NewCategoryRepository(db, log),
+		BannerRepo:       NewBannerRepository(db, log),
+		OrderRepo:        NewOrderRepository(db, log),
+		AddressRepo:      NewAddressRepository(db, log),
+		CartRepo:         NewCartRepository(db, log),
+		PromotionRepo:    NewPromotionRepository(db, log),
+		UserRepo:         NewUserRepository(db, log),
+		OrderMessageRepo: NewOrderMessageRepository(db, log),
+		ShipmentRepo:     NewShipmentRepository(db, log),
+		PaymentRepo:      NewPaymentRepository(db, log),
+		WalletRepo:       NewWalletRepository(db, log),
+		GiftCardRepo:     NewGiftCardRepository(db, log),
+		ReconcileRepo:    NewReconciliationRepository(db, log),
+		ShippingRepo:     NewShippingRepository(db, log),
+		RegionRepo:       NewRegionRepository(db, log),
+		PickupRepo:       NewPickupRepository(db, log),
 	}
 }
 
 // Repository interfaces for order and address
 type OrderRepository interface {
+	// CreateOrder reserves stock, debits order.WalletAmount from the
+	// customer's wallet and redeems order.GiftCardAmount from its gift card
+	// in the same transaction as the insert.
 	CreateOrder(ctx context.Context, order *entity.Order) error
 	GetOrderByID(ctx context.Context, id uint) (*entity.Order, error)
+	GetOrdersByIDs(ctx context.Context, ids []uint) ([]entity.Order, error)
 	ListOrdersByCustomer(ctx context.Context, customerID uint, limit, offset int) ([]entity.Order, int64, error)
+	GetOrderByNumber(ctx context.Context, number string) (*entity.Order, error)
+	ListOrders(ctx context.Context, page, limit int, search, status string) ([]entity.Order, int64, error)
+	NextOrderSequence(ctx context.Context, day string) (int, error)
+	UpdateOrderStatus(ctx context.Context, id uint, status string) error
+	StreamOrderLines(ctx context.Context, from, to time.Time, status string, fn func(OrderExportRow) error) error
+	CountCustomerOrders(ctx context.Context, customerID uint, paymentMethod, status string) (int64, error)
+	// CountPlacedOrders counts the customer's orders that were not cancelled
+	CountPlacedOrders(ctx context.Context, customerID uint) (int64, error)
+	ListExpiredUnpaidOrders(ctx context.Context, now time.Time, limit int) ([]entity.Order, error)
+	// CancelOrder cancels o if it is still in o.Status, restocking its
+	// variants, restoring its voucher usage and crediting back what was paid
+	// from the wallet or a gift card. It reports whether it did.
+	CancelOrder(ctx context.Context, o *entity.Order) (bool, error)
+	GetOrderByPickupCode(ctx context.Context, code string) (*entity.Order, error)
+	// MarkPickedUp moves a ready for pickup order to picked up and reports
+	// whether it did.
+	MarkPickedUp(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+// Order message thread repository
+type OrderMessageRepository interface {
+	CreateMessage(ctx context.Context, m *entity.OrderMessage) error
+	ListMessages(ctx context.Context, orderID uint, includeInternal bool) ([]entity.OrderMessage, error)
+	MarkReadByCustomer(ctx context.Context, orderID uint, at time.Time) error
+	MarkReadByAdmin(ctx context.Context, orderID uint, at time.Time) error
+}
+
+// Shipment repository. Writes also store the order status derived from the
+// order's shipments, in the same transaction.
+type ShipmentRepository interface {
+	CreateShipment(ctx context.Context, sh *entity.Shipment, orderStatus string) error
+	GetShipmentByID(ctx context.Context, id uint) (*entity.Shipment, error)
+	UpdateShipmentStatus(ctx context.Context, sh *entity.Shipment, orderStatus string) error
+	// ListTrackableShipments returns shipments on their way with one of
+	// carriers, with their events, in id order after afterID.
+	ListTrackableShipments(ctx context.Context, carriers []string, afterID uint, limit int) ([]entity.Shipment, error)
+	// AddTrackingEvents stores events not stored yet and the tracking status of sh.
+	AddTrackingEvents(ctx context.Context, sh *entity.Shipment, events []entity.ShipmentEvent) error
+}
+
+// Payment repository. UpdatePayment also moves a created order to
+// orderStatus in the same transaction unless orderStatus is empty, and
+// reports whether it did; when the order is no longer created the payment
+// is flagged NeedsRefund instead.
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, p *entity.Payment) error
+	GetPaymentByChargeID(ctx context.Context, chargeID string) (*entity.Payment, error)
+	GetLatestPaymentByOrder(ctx context.Context, orderID uint) (*entity.Payment, error)
+	UpdatePayment(ctx context.Context, p *entity.Payment, orderStatus string) (bool, error)
+	// RefundToWallet stores the refund recorded on p and credits e to the
+	// customer's wallet in one transaction.
+	RefundToWallet(ctx context.Context, p *entity.Payment, e *entity.WalletEntry) error
+	// RecordEvent stores ev unless an event with the same method and id
+	// exists, reporting whether it was stored.
+	RecordEvent(ctx context.Context, ev *entity.PaymentEvent) (bool, error)
+	DeleteEvent(ctx context.Context, id uint) error
+	CreateWebhookLog(ctx context.Context, l *entity.PaymentWebhookLog) error
+	// ListSettledPayments returns the payments of method paid in [from, to).
+	ListSettledPayments(ctx context.Context, method string, from, to time.Time) ([]entity.Payment, error)
+}
+
+// Reconciliation repository. CreateRun stores the run with its items.
+type ReconciliationRepository interface {
+	CreateRun(ctx context.Context, run *entity.ReconciliationRun) error
+	ListRuns(ctx context.Context, page, limit int) ([]entity.ReconciliationRun, int64, error)
+	GetRun(ctx context.Context, id uint) (*entity.ReconciliationRun, error)
+	ListItems(ctx context.Context, runID uint, issue string) ([]entity.ReconciliationItem, error)
+}
+
+// Shipping repository. SaveZone and SaveMethod replace the zone's rules and
+// the method's rates with the ones given.
+type ShippingRepository interface {
+	ListZones(ctx context.Context) ([]entity.ShippingZone, error)
+	GetZone(ctx context.Context, id uint) (*entity.ShippingZone, error)
+	SaveZone(ctx context.Context, z *entity.ShippingZone) error
+	DeleteZone(ctx context.Context, id uint) error
+	GetMethod(ctx context.Context, id uint) (*entity.ShippingMethod, error)
+	SaveMethod(ctx context.Context, m *entity.ShippingMethod) error
+	DeleteMethod(ctx context.Context, id uint) error
+}
+
+// Pickup location repository. ListLocations returns only active locations
+// unless all is set.
+type PickupRepository interface {
+	ListLocations(ctx context.Context, all bool) ([]entity.PickupLocation, error)
+	GetLocation(ctx context.Context, id uint) (*entity.PickupLocation, error)
+	SaveLocation(ctx context.Context, l *entity.PickupLocation) error
+	DeleteLocation(ctx context.Context, id uint) error
+}
+
+// Region repository over the seeded provinces and cities
+type RegionRepository interface {
+	ListProvinces(ctx context.Context) ([]entity.Province, error)
+	GetProvince(ctx context.Context, id uint) (*entity.Province, error)
+	ListCities(ctx context.Context, provinceID uint, search string) ([]entity.City, error)
+	GetCity(ctx context.Context, id uint) (*entity.City, error)
 }
 
 type AddressRepository interface {
 	CreateAddress(ctx context.Context, addr *entity.Address) error
 	UpdateAddress(ctx context.Context, addr *entity.Address) error
+	// DeleteAddress sets deleted_at, which the other methods filter out, so
+	// orders keep their address; it promotes the oldest remaining address when
+	// the default is deleted
 	DeleteAddress(ctx context.Context, id uint) error
 	GetAddressByID(ctx context.Context, id uint) (*entity.Address, error)
+	// ListAddressesByCustomer returns the customer's addresses oldest first
 	ListAddressesByCustomer(ctx context.Context, customerID uint) ([]entity.Address, error)
 	SetDefaultAddress(ctx context.Context, customerID uint, addressID uint) error
 }
 
-// Cart repository
+// Cart repository. AddItems adds all the items or none of them.
 type CartRepository interface {
 	GetCartByCustomer(ctx context.Context, customerID uint) (*entity.Cart, error)
 	ClearCart(ctx context.Context, customerID uint) error
+	AddItems(ctx context.Context, customerID uint, items []entity.CartItem) error
 }
 
-// Promotion repository
+// Promotion repository. GetByVoucherCode and GetPromotionByID load the
+// attached products and categories; attaching skips the ones attached already.
 type PromotionRepository interface {
 	GetByVoucherCode(ctx context.Context, code string) (*entity.Promotion, error)
 	DecrementUsage(ctx context.Context, id uint) error
+	RestoreUsage(ctx context.Context, id uint) error
+	ListPromotions(ctx context.Context, page, limit int, search string) ([]entity.Promotion, int64, error)
+	GetPromotionByID(ctx context.Context, id uint) (*entity.Promotion, error)
+	CreatePromotion(ctx context.Context, p *entity.Promotion) error
+	UpdatePromotion(ctx context.Context, p *entity.Promotion) error
+	DeletePromotion(ctx context.Context, id uint) error
+	TogglePublished(ctx context.Context, id uint, published bool) error
+	IsVoucherCodeExists(ctx context.Context, code string, excludeID uint) (bool, error)
+	// ExistingProductIDs returns which of ids are products
+	ExistingProductIDs(ctx context.Context, ids []uint) ([]uint, error)
+	AttachProducts(ctx context.Context, promotionID uint, productIDs []uint) error
+	DetachProduct(ctx context.Context, promotionID, productID uint) error
+	// ExistingCategoryIDs returns which of ids are categories
+	ExistingCategoryIDs(ctx context.Context, ids []uint) ([]uint, error)
+	AttachCategories(ctx context.Context, promotionID uint, categoryIDs []uint) error
+	DetachCategory(ctx context.Context, promotionID, categoryID uint) error
+}
+
+// Wallet repository. Every balance change writes a ledger entry in the same
+// transaction; Debit fails without writing when the balance is too low.
+type WalletRepository interface {
+	GetBalance(ctx context.Context, customerID uint) (float64, error)
+	ListEntries(ctx context.Context, customerID uint, limit, offset int) ([]entity.WalletEntry, int64, error)
+	Credit(ctx context.Context, e *entity.WalletEntry) error
+	Debit(ctx context.Context, e *entity.WalletEntry) error
+}
+
+// Gift card repository. CreateGiftCards sets the IDs on the given cards.
+// Redemptions are written by OrderRepository together with the order they
+// pay for.
+type GiftCardRepository interface {
+	CreateGiftCards(ctx context.Context, cards []entity.GiftCard) error
+	GetGiftCardByID(ctx context.Context, id uint) (*entity.GiftCard, error)
+	GetGiftCardByCode(ctx context.Context, code string) (*entity.GiftCard, error)
+	ListGiftCards(ctx context.Context, page, limit int, batchID string) ([]entity.GiftCard, int64, error)
+	ListRedemptions(ctx context.Context, giftCardID uint) ([]entity.GiftCardRedemption, error)
 }
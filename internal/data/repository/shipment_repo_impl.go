@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+type shipmentRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewShipmentRepository(db *gorm.DB, log *zap.Logger) ShipmentRepository {
+	return &shipmentRepo{db: db, log: log}
+}
+
+// CreateShipment stores sh with its items and moves the order to orderStatus.
+func (r *shipmentRepo) CreateShipment(ctx context.Context, sh *entity.Shipment, orderStatus string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(sh).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Order{}).Where("id = ?", sh.OrderID).Update("status", orderStatus).Error
+	})
+}
+
+func (r *shipmentRepo) GetShipmentByID(ctx context.Context, id uint) (*entity.Shipment, error) {
+	var sh entity.Shipment
+	if err := r.db.WithContext(ctx).Preload("Items").First(&sh, id).Error; err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+// UpdateShipmentStatus saves the status of sh and moves the order to orderStatus.
+func (r *shipmentRepo) UpdateShipmentStatus(ctx context.Context, sh *entity.Shipment, orderStatus string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Shipment{}).Where("id = ?", sh.ID).
+			Updates(map[string]any{"status": sh.Status, "delivered_at": sh.DeliveredAt}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entity.Order{}).Where("id = ?", sh.OrderID).Update("status", orderStatus).Error
+	})
+}
+
+func (r *shipmentRepo) ListTrackableShipments(ctx context.Context, carriers []string, afterID uint, limit int) ([]entity.Shipment, error) {
+	var shipments []entity.Shipment
+	err := r.db.WithContext(ctx).Preload("Events", eventsInOrder).
+		Where("status = ? AND carrier IN ? AND id > ?", entity.ShipmentStatusShipped, carriers, afterID).
+		Order("id ASC").Limit(limit).Find(&shipments).Error
+	if err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+func (r *shipmentRepo) AddTrackingEvents(ctx context.Context, sh *entity.Shipment, events []entity.ShipmentEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(events) > 0 {
+			// a concurrent poll may have stored some of them already
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&events).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entity.Shipment{}).Where("id = ?", sh.ID).Update("tracking_status", sh.TrackingStatus).Error
+	})
+}
+
+// eventsInOrder preloads tracking events oldest first.
+func eventsInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("occurred_at ASC")
+}
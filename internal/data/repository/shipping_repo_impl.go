@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+type shippingRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewShippingRepository(db *gorm.DB, log *zap.Logger) ShippingRepository {
+	return &shippingRepo{db: db, log: log}
+}
+
+func (r *shippingRepo) ListZones(ctx context.Context) ([]entity.ShippingZone, error) {
+	var zones []entity.ShippingZone
+	err := r.db.WithContext(ctx).
+		Preload("Rules").
+		Preload("Methods", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Methods.Rates").
+		Order("id ASC").
+		Find(&zones).Error
+	if err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (r *shippingRepo) GetZone(ctx context.Context, id uint) (*entity.ShippingZone, error) {
+	var z entity.ShippingZone
+	if err := r.db.WithContext(ctx).Preload("Rules").Preload("Methods.Rates").First(&z, id).Error; err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// SaveZone creates or updates z and replaces its rules.
+func (r *shippingRepo) SaveZone(ctx context.Context, z *entity.ShippingZone) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rules", "Methods").Save(z).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("zone_id = ?", z.ID).Delete(&entity.ShippingZoneRule{}).Error; err != nil {
+			return err
+		}
+		if len(z.Rules) == 0 {
+			return nil
+		}
+		for i := range z.Rules {
+			z.Rules[i].ID = 0
+			z.Rules[i].ZoneID = z.ID
+		}
+		return tx.Create(&z.Rules).Error
+	})
+}
+
+// DeleteZone removes the zone with its rules, methods and rates.
+func (r *shippingRepo) DeleteZone(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		methods := tx.Model(&entity.ShippingMethod{}).Select("id").Where("zone_id = ?", id)
+		if err := tx.Where("method_id IN (?)", methods).Delete(&entity.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("zone_id = ?", id).Delete(&entity.ShippingMethod{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("zone_id = ?", id).Delete(&entity.ShippingZoneRule{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.ShippingZone{}, id).Error
+	})
+}
+
+func (r *shippingRepo) GetMethod(ctx context.Context, id uint) (*entity.ShippingMethod, error) {
+	var m entity.ShippingMethod
+	if err := r.db.WithContext(ctx).Preload("Rates").First(&m, id).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// SaveMethod creates or updates m and replaces its rates.
+func (r *shippingRepo) SaveMethod(ctx context.Context, m *entity.ShippingMethod) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Select("*") so Active=false is written on update
+		if err := tx.Select("*").Omit("Rates").Save(m).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("method_id = ?", m.ID).Delete(&entity.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		if len(m.Rates) == 0 {
+			return nil
+		}
+		for i := range m.Rates {
+			m.Rates[i].ID = 0
+			m.Rates[i].MethodID = m.ID
+		}
+		return tx.Create(&m.Rates).Error
+	})
+}
+
+func (r *shippingRepo) DeleteMethod(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("method_id = ?", id).Delete(&entity.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.ShippingMethod{}, id).Error
+	})
+}
@@ -36,6 +36,9 @@ type StockRepository interface {
 	// Delete stok (set ke 0)
 	DeleteStock(ctx context.Context, variantID uint) error
 
+	// Berat variant dalam gram, dipakai untuk ongkos kirim
+	SetWeight(ctx context.Context, variantID uint, grams int) error
+
 	// Util untuk dropdown: ambil variant beserta nama produk (pagination + search optional)
 	ListVariantsForDropdown(ctx context.Context, page, pageSize int, search string) ([]StockRow, int64, error)
 
@@ -98,6 +101,7 @@ func (r *stockRepositoryImpl) GetVariantStock(ctx context.Context, variantID uin
 	if err := r.DB.WithContext(ctx).
 		Preload("Product").
 		Preload("Product.Category").
+		Preload("Product.Photos").
 		First(&v, "id = ?", variantID).Error; err != nil {
 		return nil, err
 	}
@@ -138,6 +142,15 @@ func (r *stockRepositoryImpl) DeleteStock(ctx context.Context, variantID uint) e
 		Update("stock", 0).Error
 }
 
+func (r *stockRepositoryImpl) SetWeight(ctx context.Context, variantID uint, grams int) error {
+	if grams < 0 {
+		return errors.New("weight must be >= 0")
+	}
+	return r.DB.WithContext(ctx).Model(&entity.ProductVariant{}).
+		Where("id = ?", variantID).
+		Update("weight", grams).Error
+}
+
 func (r *stockRepositoryImpl) ListVariantsForDropdown(ctx context.Context, page, pageSize int, search string) ([]StockRow, int64, error) {
 	if page < 1 {
 		page = 1
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+type walletRepo struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewWalletRepository(db *gorm.DB, log *zap.Logger) WalletRepository {
+	return &walletRepo{db: db, log: log}
+}
+
+// GetBalance returns the customer's balance; customers without a wallet have none.
+func (r *walletRepo) GetBalance(ctx context.Context, customerID uint) (float64, error) {
+	var balances []float64
+	err := r.db.WithContext(ctx).Model(&entity.Wallet{}).Where("customer_id = ?", customerID).Pluck("balance", &balances).Error
+	if err != nil || len(balances) == 0 {
+		return 0, err
+	}
+	return balances[0], nil
+}
+
+func (r *walletRepo) ListEntries(ctx context.Context, customerID uint, limit, offset int) ([]entity.WalletEntry, int64, error) {
+	var entries []entity.WalletEntry
+	var total int64
+	q := r.db.WithContext(ctx).Model(&entity.WalletEntry{}).Where("customer_id = ?", customerID)
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *walletRepo) Credit(ctx context.Context, e *entity.WalletEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return creditWallet(tx, e)
+	})
+}
+
+func (r *walletRepo) Debit(ctx context.Context, e *entity.WalletEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return debitWallet(tx, e)
+	})
+}
+
+// creditWallet adds e.Amount to the customer's wallet, creating it if needed,
+// and writes e to the ledger. tx must be a transaction.
+func creditWallet(tx *gorm.DB, e *entity.WalletEntry) error {
+	var balance float64
+	err := tx.Raw(
+		`INSERT INTO wallets (customer_id, balance, created_at, updated_at) VALUES (?, ?, NOW(), NOW())
+		 ON CONFLICT (customer_id) DO UPDATE SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+		 RETURNING balance`, e.CustomerID, e.Amount).Scan(&balance).Error
+	if err != nil {
+		return err
+	}
+	e.Type = entity.WalletEntryCredit
+	e.BalanceAfter = balance
+	return tx.Create(e).Error
+}
+
+// debitWallet takes e.Amount from the customer's wallet and writes e to the
+// ledger. The conditional update keeps concurrent debits from overdrawing.
+// tx must be a transaction.
+func debitWallet(tx *gorm.DB, e *entity.WalletEntry) error {
+	var balances []float64
+	err := tx.Raw(
+		`UPDATE wallets SET balance = balance - ?, updated_at = NOW()
+		 WHERE customer_id = ? AND balance >= ?
+		 RETURNING balance`, e.Amount, e.CustomerID, e.Amount).Scan(&balances).Error
+	if err != nil {
+		return err
+	}
+	if len(balances) == 0 {
+		return errors.New("insufficient wallet balance")
+	}
+	e.Type = entity.WalletEntryDebit
+	e.BalanceAfter = balances[0]
+	return tx.Create(e).Error
+}
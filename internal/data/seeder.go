@@ -12,6 +12,11 @@ import (
 func SeedAll(db *gorm.DB) error {
 	return db.Transaction(func(tx *gorm.DB) error {
 		seeds := dataSeeds()
+		provinces, cities, err := regionSeeds()
+		if err != nil {
+			return fmt.Errorf("region seeder fail with %s", err.Error())
+		}
+		seeds = append(seeds, provinces, cities)
 		for i := range seeds {
 			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(seeds[i]).Error
 			if nil != err {
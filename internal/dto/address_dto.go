@@ -1,14 +1,46 @@
 package dto
 
+// CreateAddressRequest is a structured address. Province and city come from
+// the region lookup endpoints; Address holds the street and house number.
 type CreateAddressRequest struct {
-	Fullname string `json:"fullname" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Address  string `json:"address" binding:"required"`
+	Fullname   string `json:"fullname" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Phone      string `json:"phone" binding:"required"`
+	Address    string `json:"address" binding:"required"`
+	ProvinceID uint   `json:"province_id" binding:"required"`
+	// CityID picks a city from the region dataset; City names one it does not list
+	CityID     uint   `json:"city_id" binding:"required_without=City"`
+	City       string `json:"city" binding:"omitempty,max=100"`
+	District   string `json:"district" binding:"required"`
+	PostalCode string `json:"postal_code" binding:"required,numeric,len=5"`
 }
 
 type AddressResponse struct {
-	ID       uint   `json:"id"`
-	Fullname string `json:"fullname"`
-	Email    string `json:"email"`
-	Address  string `json:"address"`
+	ID         uint   `json:"id"`
+	Fullname   string `json:"fullname"`
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	Address    string `json:"address"`
+	ProvinceID uint   `json:"province_id"`
+	Province   string `json:"province"`
+	CityID     uint   `json:"city_id"`
+	City       string `json:"city"`
+	District   string `json:"district"`
+	PostalCode string `json:"postal_code"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+type ProvinceResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type CityResponse struct {
+	ID         uint   `json:"id"`
+	ProvinceID uint   `json:"province_id"`
+	Name       string `json:"name"`
+}
+
+type CityListQuery struct {
+	Search string `form:"search"`
 }
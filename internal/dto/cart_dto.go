@@ -11,3 +11,17 @@ type CartResponse struct {
 	Items      []CartItemResponse `json:"items"`
 	Total      float64            `json:"total"`
 }
+
+type ReorderLine struct {
+	ProductVariantID uint    `json:"product_variant_id"`
+	ProductName      string  `json:"product_name"`
+	VariantName      string  `json:"variant_name"`
+	Quantity         int     `json:"quantity"`
+	UnitPrice        float64 `json:"unit_price,omitempty"`
+	Reason           string  `json:"reason,omitempty"`
+}
+
+type ReorderResponse struct {
+	Added   []ReorderLine `json:"added"`
+	Skipped []ReorderLine `json:"skipped"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// IssueGiftCardRequest issues one gift card. Code is generated when empty.
+type IssueGiftCardRequest struct {
+	Code      string     `json:"code" binding:"omitempty,min=6,max=32"`
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Note      string     `json:"note"`
+}
+
+// BulkGiftCardRequest generates Count gift cards of the same amount as one batch.
+type BulkGiftCardRequest struct {
+	Count     int        `json:"count" binding:"required,gt=0,lte=1000"`
+	Amount    float64    `json:"amount" binding:"required,gt=0"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Note      string     `json:"note"`
+}
+
+type GiftCardListQuery struct {
+	Page    int    `form:"page"`
+	Limit   int    `form:"limit"`
+	BatchID string `form:"batch_id"`
+}
+
+type GiftCardResponse struct {
+	ID             uint                         `json:"id"`
+	Code           string                       `json:"code"`
+	InitialBalance float64                      `json:"initial_balance"`
+	Balance        float64                      `json:"balance"`
+	ExpiresAt      *time.Time                   `json:"expires_at"`
+	BatchID        string                       `json:"batch_id,omitempty"`
+	Note           string                       `json:"note"`
+	IssuedBy       string                       `json:"issued_by"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	Redemptions    []GiftCardRedemptionResponse `json:"redemptions,omitempty"`
+}
+
+type GiftCardRedemptionResponse struct {
+	OrderID      uint      `json:"order_id"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GiftCardBalanceResponse is what customers see when checking a code.
+type GiftCardBalanceResponse struct {
+	Code      string     `json:"code"`
+	Balance   float64    `json:"balance"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type BulkGiftCardResponse struct {
+	BatchID string             `json:"batch_id"`
+	Items   []GiftCardResponse `json:"items"`
+}
+
+type GiftCardListResponse struct {
+	Items        []GiftCardResponse `json:"items"`
+	CurrentPage  int                `json:"current_page"`
+	Limit        int                `json:"limit"`
+	TotalPages   int                `json:"total_pages"`
+	TotalRecords int64              `json:"total_records"`
+}
@@ -1,21 +1,166 @@
 package dto
 
+import "time"
+
 type CreateOrderRequest struct {
-	AddressID     uint    `json:"address_id" binding:"required"`
-	PaymentMethod string  `json:"payment_method" binding:"required"`
-	Note          *string `json:"note"`
-	VoucherCode   *string `json:"voucher_code"`
+	AddressID     uint        `json:"address_id"` // the default address when empty
+	PaymentMethod string      `json:"payment_method" binding:"required"`
+	Note          *string     `json:"note"`
+	VoucherCode   *string     `json:"voucher_code"`
+	BuyNow        *BuyNowItem `json:"buy_now"`
+	// DeliveryMode is shipping unless pickup, which needs a PickupLocationID
+	DeliveryMode     string `json:"delivery_mode" binding:"omitempty,oneof=shipping pickup"`
+	PickupLocationID *uint  `json:"pickup_location_id"`
+	// ShippingMethodID picks one of the quoted shipping options; the cheapest when empty
+	ShippingMethodID *uint `json:"shipping_method_id"`
+	// GiftCardCode pays as much of the order as the card's balance covers
+	GiftCardCode *string `json:"gift_card_code"`
+	// WalletAmount pays part of the rest from the wallet balance, capped at what is left to pay
+	WalletAmount *float64 `json:"wallet_amount" binding:"omitempty,gt=0"`
+}
+
+// BuyNowItem checks out a single variant directly, leaving the cart untouched.
+type BuyNowItem struct {
+	ProductVariantID uint `json:"product_variant_id" binding:"required"`
+	Quantity         int  `json:"quantity" binding:"required,gt=0"`
 }
 
 type OrderItemDTO struct {
+	ID               uint    `json:"id"`
 	ProductVariantID uint    `json:"product_variant_id"`
+	ProductName      string  `json:"product_name"`
+	VariantName      string  `json:"variant_name"`
+	SKU              string  `json:"sku"`
+	PhotoURL         string  `json:"photo_url"`
 	Quantity         int     `json:"quantity"`
 	UnitPrice        float64 `json:"unit_price"`
 }
 
+type OrderAddressDTO struct {
+	Fullname string `json:"fullname"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Address  string `json:"address"`
+}
+
 type OrderResponse struct {
-	ID     uint           `json:"id"`
-	Items  []OrderItemDTO `json:"items"`
-	Total  float64        `json:"total"`
-	Status string         `json:"status"`
+	ID               uint            `json:"id"`
+	OrderNumber      string          `json:"order_number"`
+	Items            []OrderItemDTO  `json:"items"`
+	ShippingAddress  OrderAddressDTO `json:"shipping_address"`
+	Subtotal         float64         `json:"subtotal"`
+	Discount         float64         `json:"discount"`
+	ShippingFee      float64         `json:"shipping_fee"`
+	ShippingMethod   string          `json:"shipping_method"`
+	TotalWeight      int             `json:"total_weight"`
+	DeliveryMode     string          `json:"delivery_mode"`
+	PickupLocationID *uint           `json:"pickup_location_id,omitempty"`
+	PickupCode       string          `json:"pickup_code,omitempty"`
+	PickedUpAt       *time.Time      `json:"picked_up_at,omitempty"`
+	Tax              float64         `json:"tax"`
+	GrandTotal       float64         `json:"grand_total"`
+	Total            float64         `json:"total"`
+	WalletAmount     float64         `json:"wallet_amount"`
+	GiftCardAmount   float64         `json:"gift_card_amount"`
+	AmountDue        float64         `json:"amount_due"`
+	PaymentMethod    string          `json:"payment_method"`
+	Status           string          `json:"status"`
+	Shipments        []ShipmentDTO   `json:"shipments"`
+	PaymentExpiresAt *time.Time      `json:"payment_expires_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+type ShipmentDTO struct {
+	ID             uint               `json:"id"`
+	TrackingNumber string             `json:"tracking_number"`
+	Carrier        string             `json:"carrier"`
+	Status         string             `json:"status"`
+	TrackingStatus string             `json:"tracking_status"`
+	ShippedAt      time.Time          `json:"shipped_at"`
+	DeliveredAt    *time.Time         `json:"delivered_at"`
+	Items          []ShipmentItemDTO  `json:"items"`
+	Events         []ShipmentEventDTO `json:"events"`
+}
+
+type ShipmentEventDTO struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+type ShipmentItemDTO struct {
+	OrderItemID uint `json:"order_item_id"`
+	Quantity    int  `json:"quantity"`
+}
+
+type CheckoutQuoteResponse struct {
+	Items          []OrderItemDTO `json:"items"`
+	Subtotal       float64        `json:"subtotal"`
+	Discount       float64        `json:"discount"`
+	ShippingFee    float64        `json:"shipping_fee"`
+	Tax            float64        `json:"tax"`
+	GrandTotal     float64        `json:"grand_total"`
+	WalletAmount   float64        `json:"wallet_amount"`
+	GiftCardAmount float64        `json:"gift_card_amount"`
+	AmountDue      float64        `json:"amount_due"`
+	// ShippingMethodID is the selected option, nil when the store has no shipping zones
+	ShippingMethodID *uint                    `json:"shipping_method_id"`
+	ShippingOptions  []ShippingOptionResponse `json:"shipping_options"`
+	TotalWeight      int                      `json:"total_weight"`
+	DeliveryMode     string                   `json:"delivery_mode"`
+	Valid            bool                     `json:"valid"`
+	Errors           []string                 `json:"errors"`
+}
+
+type AdminOrderListQuery struct {
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+	Search string `form:"search"`
+	Status string `form:"status"`
+}
+
+type AdminOrderListResponse struct {
+	Items        []OrderResponse `json:"items"`
+	CurrentPage  int             `json:"current_page"`
+	Limit        int             `json:"limit"`
+	TotalPages   int             `json:"total_pages"`
+	TotalRecords int64           `json:"total_records"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status         string  `json:"status" binding:"required"`
+	TrackingNumber *string `json:"tracking_number"`
+}
+
+// CreateShipmentRequest ships the listed order items; leave Items empty to
+// ship everything that has not shipped yet. Without a tracking number the
+// shipment is booked with Carrier, which must be a registered carrier.
+type CreateShipmentRequest struct {
+	TrackingNumber string                `json:"tracking_number"`
+	Carrier        string                `json:"carrier"`
+	Items          []ShipmentItemRequest `json:"items" binding:"dive"`
+}
+
+type ShipmentItemRequest struct {
+	OrderItemID uint `json:"order_item_id" binding:"required"`
+	Quantity    int  `json:"quantity" binding:"required,gt=0"`
+}
+
+type UpdateShipmentStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type OrderExportQuery struct {
+	Format string    `form:"format"`
+	From   time.Time `form:"from" time_format:"2006-01-02"`
+	To     time.Time `form:"to" time_format:"2006-01-02"`
+	Status string    `form:"status"`
+}
+
+// FulfillmentQuery selects processing orders for pick lists and packing slips,
+// e.g. ?order_ids=1&order_ids=2&format=pdf
+type FulfillmentQuery struct {
+	OrderIDs []uint `form:"order_ids"`
+	Format   string `form:"format"` // pdf (default) or csv
 }
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type CreateOrderMessageRequest struct {
+	Body     string `json:"body" binding:"required"`
+	Internal bool   `json:"internal"` // admin only; ignored for customers
+}
+
+type OrderMessageResponse struct {
+	ID               uint       `json:"id"`
+	AuthorID         uint       `json:"author_id"`
+	AuthorRole       string     `json:"author_role"`
+	Body             string     `json:"body"`
+	Internal         bool       `json:"internal"`
+	ReadByCustomerAt *time.Time `json:"read_by_customer_at"`
+	ReadByAdminAt    *time.Time `json:"read_by_admin_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type OrderMessageListResponse struct {
+	Items  []OrderMessageResponse `json:"items"`
+	Unread int                    `json:"unread"`
+}
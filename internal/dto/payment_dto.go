@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type PaymentResponse struct {
+	OrderID        uint       `json:"order_id"`
+	Method         string     `json:"method"`
+	ChargeID       string     `json:"charge_id"`
+	Amount         float64    `json:"amount"`
+	RefundedAmount float64    `json:"refunded_amount"`
+	Status         string     `json:"status"`
+	PaymentURL     string     `json:"payment_url"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CollectedBy    string     `json:"collected_by,omitempty"`
+	NeedsRefund    bool       `json:"needs_refund"`
+}
+
+// RefundPaymentRequest refunds Amount, or everything not refunded yet when
+// empty. ToWallet credits the customer's wallet instead of the provider.
+type RefundPaymentRequest struct {
+	Amount   *float64 `json:"amount" binding:"omitempty,gt=0"`
+	ToWallet bool     `json:"to_wallet"`
+}
+
+// CollectCODRequest records cash collected by the courier on delivery.
+type CollectCODRequest struct {
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	CollectedBy string  `json:"collected_by" binding:"required"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+type PickupLocationRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Address      string `json:"address" binding:"required"`
+	Phone        string `json:"phone"`
+	OpeningHours string `json:"opening_hours"`
+	Active       *bool  `json:"active"`
+}
+
+type PickupLocationResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	Phone        string `json:"phone"`
+	OpeningHours string `json:"opening_hours"`
+	Active       bool   `json:"active"`
+}
+
+// VerifyPickupRequest carries the code staff scanned from the customer.
+type VerifyPickupRequest struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -0,0 +1,82 @@
+package dto
+
+import "time"
+
+type PromotionListQuery struct {
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+	Search string `form:"search"`
+}
+
+// PromotionRequest creates or replaces a promotion. Discount is a percentage
+// for "percentage" promotions, an amount off the order for "fixed" ones and
+// an amount off every unit for "per_item" ones; UsageLimit is how many more
+// orders can use it. The remaining fields are conditions, unused when empty.
+type PromotionRequest struct {
+	Name           string    `json:"name" binding:"required"`
+	Type           string    `json:"type" binding:"required,oneof=percentage fixed per_item"`
+	StartDate      time.Time `json:"start_date" binding:"required"`
+	EndDate        time.Time `json:"end_date" binding:"required"`
+	Discount       float64   `json:"discount" binding:"required,gt=0"`
+	MaxDiscount    float64   `json:"max_discount" binding:"gte=0"`
+	UsageLimit     int       `json:"usage_limit" binding:"gte=0"`
+	VoucherCode    string    `json:"voucher_code" binding:"omitempty,alphanum,max=32"`
+	ShowOnCheckout bool      `json:"show_on_checkout"`
+	Published      bool      `json:"published"`
+	// MinSubtotal is the order subtotal needed before the discount applies
+	MinSubtotal    float64  `json:"min_subtotal" binding:"gte=0"`
+	FirstOrderOnly bool     `json:"first_order_only"`
+	Segment        string   `json:"segment" binding:"omitempty,oneof=new returning loyal"`
+	PaymentMethods []string `json:"payment_methods" binding:"dive,required"`
+}
+
+type PromotionPublishRequest struct {
+	Published *bool `json:"published" binding:"required"`
+}
+
+type PromotionProductsRequest struct {
+	ProductIDs []uint `json:"product_ids" binding:"required,min=1"`
+}
+
+type PromotionCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"required,min=1"`
+}
+
+type PromotionProductResponse struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name"`
+	SKU       string `json:"sku"`
+}
+
+type PromotionCategoryResponse struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+}
+
+type PromotionResponse struct {
+	ID             uint                        `json:"id"`
+	Name           string                      `json:"name"`
+	Type           string                      `json:"type"`
+	StartDate      time.Time                   `json:"start_date"`
+	EndDate        time.Time                   `json:"end_date"`
+	Discount       float64                     `json:"discount"`
+	MaxDiscount    float64                     `json:"max_discount"`
+	MinSubtotal    float64                     `json:"min_subtotal"`
+	FirstOrderOnly bool                        `json:"first_order_only"`
+	Segment        string                      `json:"segment"`
+	PaymentMethods []string                    `json:"payment_methods"`
+	UsageLimit     int                         `json:"usage_limit"`
+	VoucherCode    string                      `json:"voucher_code"`
+	ShowOnCheckout bool                        `json:"show_on_checkout"`
+	Published      bool                        `json:"published"`
+	Products       []PromotionProductResponse  `json:"products"`
+	Categories     []PromotionCategoryResponse `json:"categories"`
+}
+
+type PromotionListResponse struct {
+	Items        []PromotionResponse `json:"items"`
+	CurrentPage  int                 `json:"current_page"`
+	Limit        int                 `json:"limit"`
+	TotalPages   int                 `json:"total_pages"`
+	TotalRecords int64               `json:"total_records"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// ReconcileRequest selects the provider and the period the uploaded
+// settlement file covers; To is inclusive.
+type ReconcileRequest struct {
+	Method string    `form:"method" binding:"required"`
+	From   time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	To     time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+}
+
+type ReconciliationItemResponse struct {
+	Issue          string  `json:"issue"`
+	Type           string  `json:"type"`
+	ChargeID       string  `json:"charge_id"`
+	OrderID        *uint   `json:"order_id"`
+	Line           int     `json:"line"`
+	RecordedAmount float64 `json:"recorded_amount"`
+	SettledAmount  float64 `json:"settled_amount"`
+}
+
+type ReconciliationRunResponse struct {
+	ID        uint                         `json:"id"`
+	Method    string                       `json:"method"`
+	FileName  string                       `json:"file_name"`
+	From      time.Time                    `json:"from"`
+	To        time.Time                    `json:"to"`
+	Rows      int                          `json:"rows"`
+	Matched   int                          `json:"matched"`
+	Flagged   int                          `json:"flagged"`
+	StartedBy string                       `json:"started_by"`
+	CreatedAt time.Time                    `json:"created_at"`
+	Items     []ReconciliationItemResponse `json:"items,omitempty"`
+}
+
+type ReconciliationListResponse struct {
+	Items        []ReconciliationRunResponse `json:"items"`
+	CurrentPage  int                         `json:"current_page"`
+	Limit        int                         `json:"limit"`
+	TotalPages   int                         `json:"total_pages"`
+	TotalRecords int64                       `json:"total_records"`
+}
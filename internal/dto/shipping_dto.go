@@ -0,0 +1,65 @@
+package dto
+
+type ShippingZoneRuleRequest struct {
+	Province     string `json:"province"`
+	PostalPrefix string `json:"postal_prefix" binding:"omitempty,numeric,max=10"`
+}
+
+// ShippingZoneRequest creates or replaces a zone. A rule with neither
+// province nor postal prefix makes the zone a catch-all.
+type ShippingZoneRequest struct {
+	Name  string                    `json:"name" binding:"required"`
+	Rules []ShippingZoneRuleRequest `json:"rules" binding:"required,min=1,dive"`
+}
+
+// ShippingRateRequest is one tier of a method; weights are in grams,
+// inclusive, and max_weight 0 means no upper bound.
+type ShippingRateRequest struct {
+	MinWeight   int     `json:"min_weight" binding:"gte=0"`
+	MaxWeight   int     `json:"max_weight" binding:"gte=0"`
+	MinSubtotal float64 `json:"min_subtotal" binding:"gte=0"`
+	Fee         float64 `json:"fee" binding:"gte=0"`
+}
+
+type ShippingMethodRequest struct {
+	Name          string                `json:"name" binding:"required"`
+	EstimatedDays string                `json:"estimated_days"`
+	Active        *bool                 `json:"active"`
+	Rates         []ShippingRateRequest `json:"rates" binding:"required,min=1,dive"`
+}
+
+type ShippingZoneRuleResponse struct {
+	Province     string `json:"province"`
+	PostalPrefix string `json:"postal_prefix"`
+}
+
+type ShippingRateResponse struct {
+	MinWeight   int     `json:"min_weight"`
+	MaxWeight   int     `json:"max_weight"`
+	MinSubtotal float64 `json:"min_subtotal"`
+	Fee         float64 `json:"fee"`
+}
+
+type ShippingMethodResponse struct {
+	ID            uint                   `json:"id"`
+	ZoneID        uint                   `json:"zone_id"`
+	Name          string                 `json:"name"`
+	EstimatedDays string                 `json:"estimated_days"`
+	Active        bool                   `json:"active"`
+	Rates         []ShippingRateResponse `json:"rates"`
+}
+
+type ShippingZoneResponse struct {
+	ID      uint                       `json:"id"`
+	Name    string                     `json:"name"`
+	Rules   []ShippingZoneRuleResponse `json:"rules"`
+	Methods []ShippingMethodResponse   `json:"methods"`
+}
+
+// ShippingOptionResponse is a method that can deliver the order being quoted.
+type ShippingOptionResponse struct {
+	MethodID      uint    `json:"method_id"`
+	Name          string  `json:"name"`
+	EstimatedDays string  `json:"estimated_days"`
+	Fee           float64 `json:"fee"`
+}
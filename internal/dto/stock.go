@@ -34,6 +34,12 @@ type SetStockRequest struct {
 	Qty       int  `json:"qty" binding:"required,gte=0"`
 }
 
+// SetWeightRequest sets the shipping weight of a variant in grams.
+type SetWeightRequest struct {
+	VariantID uint `json:"variant_id" binding:"required"`
+	Weight    int  `json:"weight" binding:"gte=0"`
+}
+
 type DeleteStockRequest struct {
 	VariantID uint `json:"variant_id" binding:"required"`
 }
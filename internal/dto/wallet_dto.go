@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type WalletEntryResponse struct {
+	ID           uint      `json:"id"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+	Reason       string    `json:"reason"`
+	OrderID      *uint     `json:"order_id"`
+	PaymentID    *uint     `json:"payment_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WalletResponse struct {
+	CustomerID   uint                  `json:"customer_id"`
+	Balance      float64               `json:"balance"`
+	Entries      []WalletEntryResponse `json:"entries"`
+	CurrentPage  int                   `json:"current_page"`
+	Limit        int                   `json:"limit"`
+	TotalRecords int64                 `json:"total_records"`
+}
+
+// WalletAdjustRequest credits a customer as compensation, or debits a correction.
+type WalletAdjustRequest struct {
+	Type    string  `json:"type" binding:"required,oneof=credit debit"`
+	Amount  float64 `json:"amount" binding:"required,gt=0"`
+	Reason  string  `json:"reason" binding:"required"`
+	OrderID *uint   `json:"order_id"`
+}
@@ -14,6 +14,7 @@ import (
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
 	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
 )
 
 func TestExpiredVoucherIntegration(t *testing.T){
@@ -26,7 +27,7 @@ func TestExpiredVoucherIntegration(t *testing.T){
 	if err := tdb.DB.Create(&expired).Error; err != nil { t.Fatalf("failed to create expired promo: %v", err) }
 
 	repo := repository.NewRepository(tdb.DB, zap.NewNop())
-	svc := usecase.NewOrderService(repo, zap.NewNop())
+	svc := usecase.NewOrderService(repo, zap.NewNop(), utils.Configuration{}, nil, testPayments(), testCarriers())
 	code := "EXPIRED"
 	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
 	_, err := svc.CreateOrder(context.Background(), req, 1)
@@ -41,7 +42,7 @@ func TestFixedVoucherIntegration(t *testing.T){
 	if err := tdb.DB.Create(&fixed).Error; err != nil { t.Fatalf("failed to create fixed promo: %v", err) }
 
 	repo := repository.NewRepository(tdb.DB, zap.NewNop())
-	svc := usecase.NewOrderService(repo, zap.NewNop())
+	svc := usecase.NewOrderService(repo, zap.NewNop(), utils.Configuration{}, nil, testPayments(), testCarriers())
 	code := "FIXED"
 	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
 	res, err := svc.CreateOrder(context.Background(), req, 1)
@@ -57,7 +58,7 @@ func TestConcurrentUsageIntegration(t *testing.T){
 	if err := tdb.DB.Create(&promo).Error; err != nil { t.Fatalf("failed to create conc promo: %v", err) }
 
 	repo := repository.NewRepository(tdb.DB, zap.NewNop())
-	svc := usecase.NewOrderService(repo, zap.NewNop())
+	svc := usecase.NewOrderService(repo, zap.NewNop(), utils.Configuration{}, nil, testPayments(), testCarriers())
 	code := "CONC"
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -11,6 +11,7 @@ import (
 	"project-app-ecommerce-golang-tim-1/internal/data/repository"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
 	"project-app-ecommerce-golang-tim-1/internal/usecase"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
 )
 
 func TestCreateOrderWithVoucherIntegration(t *testing.T){
@@ -20,7 +21,7 @@ func TestCreateOrderWithVoucherIntegration(t *testing.T){
 	// build repository using gorm DB
 	repo := repository.NewRepository(tdb.DB, zap.NewNop())
 	logger, _ := zap.NewDevelopment()
-	svc := usecase.NewOrderService(repo, logger)
+	svc := usecase.NewOrderService(repo, logger, utils.Configuration{}, nil, testPayments(), testCarriers())
 
 	// use seeded customer id 1 and voucher PROMO10
 	code := "PROMO10"
@@ -17,8 +17,24 @@ import (
 
 	"project-app-ecommerce-golang-tim-1/internal/data"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
 )
 
+// testPayments accepts the payment method used by the integration fixtures.
+func testPayments() *payment.Registry {
+	payments := payment.NewRegistry([]string{"gopay"})
+	payments.Register("gopay", payment.NewMockProvider("/payments/mock/charges", "test-secret"))
+	return payments
+}
+
+// testCarriers books shipments with a mock carrier that delivers at once.
+func testCarriers() *shipping.Registry {
+	carriers := shipping.NewRegistry()
+	carriers.Register("mock", shipping.NewMockCarrier(0))
+	return carriers
+}
+
 // TestDB wraps testcontainer and gorm DB
 type TestDB struct{
 	DB *gorm.DB
@@ -91,7 +107,7 @@ func seedMinimal(db *gorm.DB) error {
 	if err := db.Create(&a).Error; err != nil { return err }
 
 	// product + variant
-	p := entity.Product{Model: entity.Model{ID:1}, Name: "P1", SKU: "P1", Price: 100}
+	p := entity.Product{Model: entity.Model{ID:1}, Name: "P1", SKU: "P1", Price: 100, Published: true}
 	if err := db.Create(&p).Error; err != nil { return err }
 	v := entity.ProductVariant{Model: entity.Model{ID:1}, ProductID: 1, Variant: "V1", Stock: 10}
 	if err := db.Create(&v).Error; err != nil { return err }
@@ -2,10 +2,22 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
 	"project-app-ecommerce-golang-tim-1/internal/data/repository"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"strings"
+)
+
+// maxAddresses is how many addresses one customer can keep in the address book.
+const maxAddresses = 10
+
+var (
+	ErrAddressNotFound  = errors.New("address not found")
+	ErrAddressForbidden = errors.New("address belongs to another customer")
 )
 
 type AddressService interface {
@@ -26,43 +38,44 @@ func NewAddressService(repo repository.Repository, logger *zap.Logger) AddressSe
 }
 
 func (s *addressService) CreateAddress(ctx context.Context, req dto.CreateAddressRequest, customerID uint) (*dto.AddressResponse, error) {
-	a := &entity.Address{
-		CustomerID: customerID,
-		Fullname:   req.Fullname,
-		Email:      req.Email,
-		Address:    req.Address,
+	existing, err := s.repo.AddressRepo.ListAddressesByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAddresses {
+		return nil, fmt.Errorf("you can save at most %d addresses", maxAddresses)
+	}
+	// the first address is the default until the customer picks another
+	a := &entity.Address{CustomerID: customerID, IsDefault: len(existing) == 0}
+	if err := s.applyAddressRequest(ctx, a, req); err != nil {
+		return nil, err
 	}
 	if err := s.repo.AddressRepo.CreateAddress(ctx, a); err != nil {
 		return nil, err
 	}
-	return &dto.AddressResponse{ID: a.ID, Fullname: a.Fullname, Email: a.Email, Address: a.Address}, nil
+	res := toAddressResponse(*a)
+	return &res, nil
 }
 
 func (s *addressService) UpdateAddress(ctx context.Context, id uint, req dto.CreateAddressRequest, customerID uint) (*dto.AddressResponse, error) {
-	a, err := s.repo.AddressRepo.GetAddressByID(ctx, id)
+	a, err := ownedAddress(ctx, s.repo.AddressRepo, id, customerID)
 	if err != nil {
 		return nil, err
 	}
-	if a.CustomerID != customerID {
+	if err := s.applyAddressRequest(ctx, a, req); err != nil {
 		return nil, err
 	}
-	a.Fullname = req.Fullname
-	a.Email = req.Email
-	a.Address = req.Address
 	if err := s.repo.AddressRepo.UpdateAddress(ctx, a); err != nil {
 		return nil, err
 	}
-	return &dto.AddressResponse{ID: a.ID, Fullname: a.Fullname, Email: a.Email, Address: a.Address}, nil
+	res := toAddressResponse(*a)
+	return &res, nil
 }
 
 func (s *addressService) DeleteAddress(ctx context.Context, id uint, customerID uint) error {
-	a, err := s.repo.AddressRepo.GetAddressByID(ctx, id)
-	if err != nil {
+	if _, err := ownedAddress(ctx, s.repo.AddressRepo, id, customerID); err != nil {
 		return err
 	}
-	if a.CustomerID != customerID {
-		return nil
-	}
 	return s.repo.AddressRepo.DeleteAddress(ctx, id)
 }
 
@@ -73,11 +86,155 @@ func (s *addressService) ListAddresses(ctx context.Context, customerID uint) ([]
 	}
 	res := make([]dto.AddressResponse, 0, len(addrs))
 	for _, a := range addrs {
-		res = append(res, dto.AddressResponse{ID: a.ID, Fullname: a.Fullname, Email: a.Email, Address: a.Address})
+		res = append(res, toAddressResponse(a))
 	}
 	return res, nil
 }
 
 func (s *addressService) SetDefaultAddress(ctx context.Context, customerID uint, addressID uint) error {
+	if _, err := ownedAddress(ctx, s.repo.AddressRepo, addressID, customerID); err != nil {
+		return err
+	}
 	return s.repo.AddressRepo.SetDefaultAddress(ctx, customerID, addressID)
 }
+
+// ownedAddress loads the address id, which must belong to customerID.
+func ownedAddress(ctx context.Context, repo repository.AddressRepository, id uint, customerID uint) (*entity.Address, error) {
+	a, err := repo.GetAddressByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.CustomerID != customerID {
+		return nil, ErrAddressForbidden
+	}
+	return a, nil
+}
+
+// defaultAddress loads the customer's default address for checkouts that do
+// not name one.
+func defaultAddress(ctx context.Context, repo repository.AddressRepository, customerID uint) (*entity.Address, error) {
+	addrs, err := repo.ListAddressesByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range addrs {
+		if addrs[i].IsDefault {
+			return &addrs[i], nil
+		}
+	}
+	return nil, ErrAddressNotFound
+}
+
+// applyAddressRequest validates req and copies it onto a, taking the province
+// and city names from the region dataset. The dataset does not list every
+// regency, so a city can also be given by name; it keeps CityID 0 unless the
+// name matches a listed city of the province.
+func (s *addressService) applyAddressRequest(ctx context.Context, a *entity.Address, req dto.CreateAddressRequest) error {
+	phone, ok := normalizePhone(req.Phone)
+	if !ok {
+		return errors.New("invalid phone number")
+	}
+	province, err := s.repo.RegionRepo.GetProvince(ctx, req.ProvinceID)
+	if err != nil {
+		return errors.New("province not found")
+	}
+	city, err := s.addressCity(ctx, province, req)
+	if err != nil {
+		return err
+	}
+	a.Fullname = strings.TrimSpace(req.Fullname)
+	a.Email = strings.TrimSpace(req.Email)
+	a.Phone = phone
+	a.Address = strings.TrimSpace(req.Address)
+	a.ProvinceID = province.ID
+	a.Province = province.Name
+	a.CityID = city.ID
+	a.City = city.Name
+	a.District = strings.TrimSpace(req.District)
+	a.PostalCode = req.PostalCode
+	return nil
+}
+
+// addressCity resolves the city of req within province.
+func (s *addressService) addressCity(ctx context.Context, province *entity.Province, req dto.CreateAddressRequest) (*entity.City, error) {
+	if req.CityID == 0 {
+		name := strings.TrimSpace(req.City)
+		if name == "" {
+			return nil, errors.New("city is required")
+		}
+		listed, err := s.repo.RegionRepo.ListCities(ctx, province.ID, name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range listed {
+			if listed[i].ProvinceID == province.ID && strings.EqualFold(listed[i].Name, name) {
+				return &listed[i], nil
+			}
+		}
+		return &entity.City{ProvinceID: province.ID, Name: name}, nil
+	}
+	city, err := s.repo.RegionRepo.GetCity(ctx, req.CityID)
+	if err != nil {
+		return nil, errors.New("city not found")
+	}
+	if city.ProvinceID != province.ID {
+		return nil, fmt.Errorf("%s is not in %s", city.Name, province.Name)
+	}
+	return city, nil
+}
+
+// normalizePhone accepts Indonesian numbers written as 08.., 628.. or +628..
+// with spaces or dashes, and returns them as +62...
+func normalizePhone(phone string) (string, bool) {
+	digits := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+	switch {
+	case strings.HasPrefix(digits, "+62"):
+		digits = digits[3:]
+	case strings.HasPrefix(digits, "62"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = digits[1:]
+	default:
+		return "", false
+	}
+	if len(digits) < 8 || len(digits) > 12 || digits[0] == '0' {
+		return "", false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return "+62" + digits, true
+}
+
+// formatAddress writes a as a single line for labels and order history.
+func formatAddress(a entity.Address) string {
+	var parts []string
+	for _, p := range []string{a.Address, a.District, a.City, strings.TrimSpace(a.Province + " " + a.PostalCode)} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func toAddressResponse(a entity.Address) dto.AddressResponse {
+	return dto.AddressResponse{
+		ID:         a.ID,
+		Fullname:   a.Fullname,
+		Email:      a.Email,
+		Phone:      a.Phone,
+		Address:    a.Address,
+		ProvinceID: a.ProvinceID,
+		Province:   a.Province,
+		CityID:     a.CityID,
+		City:       a.City,
+		District:   a.District,
+		PostalCode: a.PostalCode,
+		IsDefault:  a.IsDefault,
+	}
+}
@@ -2,11 +2,14 @@ package usecase
 
 import (
     "context"
+    "errors"
     "testing"
 
     "go.uber.org/zap"
+    "gorm.io/gorm"
     "project-app-ecommerce-golang-tim-1/internal/data/entity"
     "project-app-ecommerce-golang-tim-1/internal/data/repository"
+    "project-app-ecommerce-golang-tim-1/internal/dto"
 )
 
 type mockAddressRepo struct{}
@@ -25,3 +28,214 @@ func TestSetDefaultAddress(t *testing.T){
         t.Fatalf("unexpected error: %v", err)
     }
 }
+
+// Region dataset with Jawa Barat and DKI Jakarta
+type memRegionRepo struct{}
+
+func (r *memRegionRepo) ListProvinces(ctx context.Context) ([]entity.Province, error) {
+	return []entity.Province{{ID: 31, Name: "DKI Jakarta"}, {ID: 32, Name: "Jawa Barat"}}, nil
+}
+
+func (r *memRegionRepo) GetProvince(ctx context.Context, id uint) (*entity.Province, error) {
+	provinces, _ := r.ListProvinces(ctx)
+	for _, p := range provinces {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memRegionRepo) ListCities(ctx context.Context, provinceID uint, search string) ([]entity.City, error) {
+	if provinceID != 32 {
+		return nil, nil
+	}
+	return []entity.City{{ID: 3273, ProvinceID: 32, Name: "Kota Bandung"}}, nil
+}
+
+func (r *memRegionRepo) GetCity(ctx context.Context, id uint) (*entity.City, error) {
+	switch id {
+	case 3273:
+		return &entity.City{ID: 3273, ProvinceID: 32, Name: "Kota Bandung"}, nil
+	case 3171:
+		return &entity.City{ID: 3171, ProvinceID: 31, Name: "Kota Jakarta Selatan"}, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func TestCreateAddress_StructuredFields(t *testing.T) {
+	svc := NewAddressService(repository.Repository{AddressRepo: &mockAddressRepo{}, RegionRepo: &memRegionRepo{}}, zap.NewNop())
+	req := dto.CreateAddressRequest{Fullname: "Zahra", Email: "zahra@example.com", Phone: "0812-3456-7890", Address: "Jl. Kebangsaan No.10",
+		ProvinceID: 32, CityID: 3273, District: "Sumur Bandung", PostalCode: "40115"}
+
+	res, err := svc.CreateAddress(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Phone != "+6281234567890" || res.Province != "Jawa Barat" || res.City != "Kota Bandung" {
+		t.Fatalf("expected a normalized phone and region names, got %+v", res)
+	}
+
+	req.CityID = 3171
+	if _, err := svc.CreateAddress(context.Background(), req, 1); err == nil || err.Error() != "Kota Jakarta Selatan is not in Jawa Barat" {
+		t.Fatalf("expected a city outside the province to be rejected, got %v", err)
+	}
+
+	// regencies missing from the dataset are taken by name
+	req.CityID, req.City = 0, "Kabupaten Garut"
+	res, err = svc.CreateAddress(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.CityID != 0 || res.City != "Kabupaten Garut" || res.Province != "Jawa Barat" {
+		t.Fatalf("expected the unlisted city by name, got %+v", res)
+	}
+	req.City = "kota bandung"
+	if res, _ = svc.CreateAddress(context.Background(), req, 1); res.CityID != 3273 || res.City != "Kota Bandung" {
+		t.Fatalf("expected a listed city given by name to be matched, got %+v", res)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	for in, want := range map[string]string{
+		"081234567890":      "+6281234567890",
+		"+62 812 3456 7890": "+6281234567890",
+		"6281234567890":     "+6281234567890",
+		"(022) 4201234":     "+62224201234",
+		"12345":             "",
+		"0812-34x6-7890":    "",
+		"+6208123456789":    "",
+	} {
+		got, ok := normalizePhone(in)
+		if got != want || ok != (want != "") {
+			t.Fatalf("normalizePhone(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestFormatAddress_SkipsEmptyParts(t *testing.T) {
+	a := entity.Address{Address: "Jl. Kebangsaan No.10", City: "Kota Bandung", Province: "Jawa Barat", PostalCode: "40115"}
+	if got := formatAddress(a); got != "Jl. Kebangsaan No.10, Kota Bandung, Jawa Barat 40115" {
+		t.Fatalf("unexpected address line %q", got)
+	}
+}
+
+// Address book kept in memory, keyed by ID
+type memAddressRepo struct {
+	mockAddressRepo
+	addrs []entity.Address
+}
+
+func (r *memAddressRepo) CreateAddress(ctx context.Context, addr *entity.Address) error {
+	addr.ID = uint(len(r.addrs) + 1)
+	r.addrs = append(r.addrs, *addr)
+	return nil
+}
+
+func (r *memAddressRepo) GetAddressByID(ctx context.Context, id uint) (*entity.Address, error) {
+	for _, a := range r.addrs {
+		if a.ID == id {
+			return &a, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memAddressRepo) ListAddressesByCustomer(ctx context.Context, customerID uint) ([]entity.Address, error) {
+	var out []entity.Address
+	for _, a := range r.addrs {
+		if a.CustomerID == customerID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func TestCreateAddress_FirstIsDefaultAndLimit(t *testing.T) {
+	repo := &memAddressRepo{}
+	svc := NewAddressService(repository.Repository{AddressRepo: repo, RegionRepo: &memRegionRepo{}}, zap.NewNop())
+	req := dto.CreateAddressRequest{Fullname: "Zahra", Email: "zahra@example.com", Phone: "081234567890", Address: "Jl. Kebangsaan No.10",
+		ProvinceID: 32, CityID: 3273, District: "Sumur Bandung", PostalCode: "40115"}
+
+	for i := 0; i < maxAddresses; i++ {
+		res, err := svc.CreateAddress(context.Background(), req, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.IsDefault != (i == 0) {
+			t.Fatalf("expected only the first address to be default, address %d has IsDefault=%v", i+1, res.IsDefault)
+		}
+	}
+	if _, err := svc.CreateAddress(context.Background(), req, 1); err == nil {
+		t.Fatalf("expected the address book limit to be enforced")
+	}
+	if res, err := svc.CreateAddress(context.Background(), req, 2); err != nil || !res.IsDefault {
+		t.Fatalf("expected another customer's first address to be default, got %+v, %v", res, err)
+	}
+}
+
+func TestAddressOwnership(t *testing.T) {
+	repo := &memAddressRepo{addrs: []entity.Address{{Model: entity.Model{ID: 1}, CustomerID: 2}}}
+	svc := NewAddressService(repository.Repository{AddressRepo: repo, RegionRepo: &memRegionRepo{}}, zap.NewNop())
+	req := dto.CreateAddressRequest{Fullname: "Zahra", Email: "zahra@example.com", Phone: "081234567890", Address: "Jl. Kebangsaan No.10",
+		ProvinceID: 32, CityID: 3273, District: "Sumur Bandung", PostalCode: "40115"}
+
+	if _, err := svc.UpdateAddress(context.Background(), 1, req, 1); !errors.Is(err, ErrAddressForbidden) {
+		t.Fatalf("expected ErrAddressForbidden on update, got %v", err)
+	}
+	if err := svc.DeleteAddress(context.Background(), 1, 1); !errors.Is(err, ErrAddressForbidden) {
+		t.Fatalf("expected ErrAddressForbidden on delete, got %v", err)
+	}
+	if err := svc.SetDefaultAddress(context.Background(), 1, 1); !errors.Is(err, ErrAddressForbidden) {
+		t.Fatalf("expected ErrAddressForbidden on set default, got %v", err)
+	}
+	if err := svc.DeleteAddress(context.Background(), 9, 1); !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected ErrAddressNotFound, got %v", err)
+	}
+}
+
+// Address repo whose database is down
+type failingAddressRepo struct{ mockAddressRepo }
+
+func (r *failingAddressRepo) GetAddressByID(ctx context.Context, id uint) (*entity.Address, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestOwnedAddress_OnlyMissingRowsAreNotFound(t *testing.T) {
+	if _, err := ownedAddress(context.Background(), &failingAddressRepo{}, 1, 1); err == nil || errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected a database error to be passed up, got %v", err)
+	}
+	if _, err := ownedAddress(context.Background(), &memAddressRepo{}, 1, 1); !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected a missing address to be not found, got %v", err)
+	}
+}
+
+func TestCreateOrder_CheckoutAddress(t *testing.T) {
+	addrs := &memAddressRepo{addrs: []entity.Address{
+		{Model: entity.Model{ID: 1}, CustomerID: 2, Fullname: "Someone Else"},
+		{Model: entity.Model{ID: 2}, CustomerID: 1, Fullname: "Office"},
+		{Model: entity.Model{ID: 3}, CustomerID: 1, Fullname: "Home", IsDefault: true},
+	}}
+	newService := func() *orderService {
+		cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 5, ProductVariant: availableVariant(5), Quantity: 1, UnitPrice: 100}}}
+		repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{}, AddressRepo: addrs, ShippingRepo: &memShippingRepo{}}
+		return &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+	}
+
+	if _, err := newService().CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}, 1); !errors.Is(err, ErrAddressForbidden) {
+		t.Fatalf("expected another customer's address to be rejected, got %v", err)
+	}
+	if _, err := newService().QuoteOrder(context.Background(), dto.CreateOrderRequest{AddressID: 9, PaymentMethod: "gopay"}, 1); !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected an unknown address to be not found, got %v", err)
+	}
+	res, err := newService().CreateOrder(context.Background(), dto.CreateOrderRequest{PaymentMethod: "gopay"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.ShippingAddress.Fullname != "Home" {
+		t.Fatalf("expected checkout to fall back to the default address, got %q", res.ShippingAddress.Fullname)
+	}
+	if _, err := newService().CreateOrder(context.Background(), dto.CreateOrderRequest{PaymentMethod: "gopay"}, 4); err == nil {
+		t.Fatalf("expected checkout without any address to fail")
+	}
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// checkout is a fully priced order that has not been written yet. It is
+// shared by CreateOrder and QuoteOrder so both run the exact same pricing,
+// voucher and stock checks.
+type checkout struct {
+	order    *entity.Order
+	lines    []promotionLine
+	promo    *entity.Promotion
+	discount float64
+	shipping []shippingOption
+	errors   []string
+}
+
+func (c *checkout) reject(msg string) {
+	c.errors = append(c.errors, msg)
+}
+
+// addItem adds qty of v at unitPrice to the order, keeping the product and
+// category around for promotion rules.
+func (c *checkout) addItem(v entity.ProductVariant, qty int, unitPrice float64) {
+	c.order.Items = append(c.order.Items, snapshotOrderItem(v, qty, unitPrice))
+	c.lines = append(c.lines, promotionLine{productID: v.ProductID, categoryID: v.Product.CategoryID, quantity: qty, unitPrice: unitPrice})
+}
+
+// err returns the first validation error, if any.
+func (c *checkout) err() error {
+	if len(c.errors) == 0 {
+		return nil
+	}
+	return errors.New(c.errors[0])
+}
+
+// prepareCheckout builds and prices the order for customerID without writing
+// anything. Validation problems are collected on the checkout; only
+// unexpected failures are returned as error.
+func (s *orderService) prepareCheckout(ctx context.Context, req dto.CreateOrderRequest, customerID uint) (*checkout, error) {
+	c := &checkout{order: &entity.Order{
+		CustomerID:    customerID,
+		Note:          utils.Deref(req.Note),
+		PaymentMethod: req.PaymentMethod,
+		Status:        entity.OrderStatusCreated,
+	}}
+
+	if req.BuyNow != nil {
+		s.addBuyNowLine(ctx, c, *req.BuyNow)
+	} else if err := s.addCartLines(ctx, c, customerID); err != nil {
+		return nil, err
+	}
+
+	// snapshot the shipping address so later edits don't rewrite history
+	addr, err := s.checkoutAddress(ctx, req.AddressID, customerID)
+	if errors.Is(err, ErrAddressNotFound) || errors.Is(err, ErrAddressForbidden) {
+		return nil, err
+	} else if err != nil {
+		c.reject(err.Error())
+	} else {
+		c.order.AddressID = addr.ID
+		c.order.ShippingName = addr.Fullname
+		c.order.ShippingEmail = addr.Email
+		c.order.ShippingPhone = addr.Phone
+		c.order.ShippingAddress = formatAddress(*addr)
+	}
+
+	for _, it := range c.order.Items {
+		c.order.TotalWeight += it.Weight * it.Quantity
+	}
+	var shippingFee float64
+	if req.DeliveryMode == entity.DeliveryModePickup {
+		s.applyPickup(ctx, c, req.PickupLocationID)
+	} else {
+		c.order.DeliveryMode = entity.DeliveryModeShipping
+		if shippingFee, err = s.applyShipping(ctx, c, addr, req.ShippingMethodID); err != nil {
+			return nil, err
+		}
+	}
+
+	// apply voucher if present
+	if req.VoucherCode != nil && *req.VoucherCode != "" {
+		if msg := s.applyVoucher(ctx, c, *req.VoucherCode); msg != "" {
+			c.reject(msg)
+		}
+	}
+
+	// totals are computed once here and persisted with the order
+	s.pricer.Price(c.order.Items, c.discount, shippingFee).applyTo(c.order)
+
+	// gift cards are spent before the wallet, which can be used any time
+	if req.GiftCardCode != nil && *req.GiftCardCode != "" {
+		if msg := s.applyGiftCard(ctx, c, *req.GiftCardCode); msg != "" {
+			c.reject(msg)
+		}
+	}
+	if req.WalletAmount != nil {
+		if err := s.applyWallet(ctx, c, *req.WalletAmount); err != nil {
+			return nil, err
+		}
+	}
+	if amountDue(*c.order) == 0 && (c.order.WalletAmount > 0 || c.order.GiftCardAmount > 0) {
+		// nothing left to collect, so no payment method is involved
+		c.order.PaymentMethod = payment.MethodWallet
+		if c.order.WalletAmount == 0 {
+			c.order.PaymentMethod = payment.MethodGiftCard
+		}
+		return c, nil
+	}
+	if err := s.checkPaymentMethod(ctx, c, customerID); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// checkoutAddress returns the address the order ships to: addressID when
+// given, which must be the customer's own, otherwise their default address.
+// An unknown or foreign addressID fails with ErrAddressNotFound or
+// ErrAddressForbidden, so handlers can answer 404 or 403.
+func (s *orderService) checkoutAddress(ctx context.Context, addressID uint, customerID uint) (*entity.Address, error) {
+	if addressID != 0 {
+		return ownedAddress(ctx, s.repo.AddressRepo, addressID, customerID)
+	}
+	addr, err := defaultAddress(ctx, s.repo.AddressRepo, customerID)
+	if errors.Is(err, ErrAddressNotFound) {
+		return nil, errors.New("add a shipping address before checking out")
+	}
+	return addr, err
+}
+
+// applyShipping picks the shipping method of the weighed order, the cheapest
+// one for the address unless methodID selects another, returning its fee.
+// Stores that have not set up shipping zones ship for free.
+func (s *orderService) applyShipping(ctx context.Context, c *checkout, addr *entity.Address, methodID *uint) (float64, error) {
+	zones, err := s.repo.ShippingRepo.ListZones(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(zones) == 0 || addr == nil {
+		return 0, nil
+	}
+	zone := matchShippingZone(zones, *addr)
+	if zone == nil {
+		c.reject("we do not ship to this address")
+		return 0, nil
+	}
+	// rate tiers look at the value of the goods, before any discount
+	subtotal := s.pricer.Price(c.order.Items, 0, 0).Subtotal
+	c.shipping = shippingOptions(*zone, c.order.TotalWeight, subtotal)
+	if len(c.shipping) == 0 {
+		c.reject("no shipping method can deliver this order")
+		return 0, nil
+	}
+
+	chosen := c.shipping[0]
+	if methodID != nil {
+		found := false
+		for _, o := range c.shipping {
+			if o.method.ID == *methodID {
+				chosen, found = o, true
+			}
+		}
+		if !found {
+			c.reject("shipping method is not available for this address")
+			return 0, nil
+		}
+	}
+	id := chosen.method.ID
+	c.order.ShippingMethodID = &id
+	c.order.ShippingMethod = chosen.method.Name
+	return chosen.fee, nil
+}
+
+// applyWallet pays up to amount of the order from the customer's wallet. The
+// balance is checked again when the order is written, so a concurrent
+// checkout can never spend it twice.
+func (s *orderService) applyWallet(ctx context.Context, c *checkout, amount float64) error {
+	balance, err := s.repo.WalletRepo.GetBalance(ctx, c.order.CustomerID)
+	if err != nil {
+		return err
+	}
+	if roundMoney(amount) > roundMoney(balance) {
+		c.reject(fmt.Sprintf("insufficient wallet balance, available %.2f", balance))
+		return nil
+	}
+	c.order.WalletAmount = roundMoney(math.Min(amount, amountDue(*c.order)))
+	return nil
+}
+
+// applyGiftCard pays as much of the order as the gift card's balance covers.
+// Like the wallet, the balance is taken again when the order is written.
+func (s *orderService) applyGiftCard(ctx context.Context, c *checkout, code string) string {
+	g, err := s.repo.GiftCardRepo.GetGiftCardByCode(ctx, normalizeGiftCardCode(code))
+	if err != nil {
+		return "invalid gift card"
+	}
+	if g.ExpiresAt != nil && g.ExpiresAt.Before(time.Now()) {
+		return "gift card has expired"
+	}
+	if g.Balance <= 0 {
+		return "gift card has no balance left"
+	}
+	c.order.GiftCardID = &g.ID
+	c.order.GiftCardAmount = roundMoney(math.Min(g.Balance, amountDue(*c.order)))
+	return ""
+}
+
+// amountDue is what is left to pay for o after its wallet and gift card amounts.
+func amountDue(o entity.Order) float64 {
+	return roundMoney(o.GrandTotal - o.WalletAmount - o.GiftCardAmount)
+}
+
+// addCartLines snapshots the products in the customer's cart as they are right now.
+func (s *orderService) addCartLines(ctx context.Context, c *checkout, customerID uint) error {
+	cart, err := s.repo.CartRepo.GetCartByCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if len(cart.Items) == 0 {
+		c.reject("cart is empty")
+	}
+	for _, it := range cart.Items {
+		if msg := checkVariantAvailable(it.ProductVariant, it.Quantity); msg != "" {
+			c.reject(msg)
+		}
+		c.addItem(it.ProductVariant, it.Quantity, it.UnitPrice)
+	}
+	return nil
+}
+
+// addBuyNowLine prices a single variant at its current price, bypassing the cart.
+func (s *orderService) addBuyNowLine(ctx context.Context, c *checkout, item dto.BuyNowItem) {
+	v, err := s.repo.StockRepo.GetVariantStock(ctx, item.ProductVariantID)
+	if err != nil {
+		c.reject("product variant not found")
+		return
+	}
+	if msg := checkVariantAvailable(*v, item.Quantity); msg != "" {
+		c.reject(msg)
+	}
+	c.addItem(*v, item.Quantity, v.Product.Price)
+}
+
+// applyVoucher runs the promotion rules for code on the order and returns
+// why the voucher cannot be used, or "" once its discount is set.
+func (s *orderService) applyVoucher(ctx context.Context, c *checkout, code string) string {
+	code = normalizeVoucherCode(code)
+	promo, err := s.repo.PromotionRepo.GetByVoucherCode(ctx, code)
+	if err != nil {
+		return "invalid voucher"
+	}
+	now := time.Now()
+	if !promo.Published || promo.StartDate.After(now) || promo.EndDate.Before(now) {
+		return "voucher not active"
+	}
+
+	// enforce usage limit
+	if promo.UsageLimit <= 0 {
+		return "voucher usage limit exceeded"
+	}
+
+	in := promotionInput{lines: c.lines, paymentMethod: c.order.PaymentMethod}
+	if promo.FirstOrderOnly || promo.Segment != "" {
+		if in.placedOrders, err = s.repo.OrderRepo.CountPlacedOrders(ctx, c.order.CustomerID); err != nil {
+			s.logger.Error("failed to count placed orders", zap.Uint("customer_id", c.order.CustomerID), zap.Error(err))
+			return "voucher cannot be applied to this order"
+		}
+	}
+	discount, msg := evaluatePromotion(*promo, in)
+	if msg != "" {
+		return msg
+	}
+	c.promo = promo
+	c.discount = discount
+	c.order.VoucherCode = &code
+	// store promotion id for audit
+	c.order.PromotionID = &promo.ID
+	return ""
+}
+
+// checkVariantAvailable returns why qty of v cannot be bought, or "" if it can.
+func checkVariantAvailable(v entity.ProductVariant, qty int) string {
+	name := v.Product.Name
+	if v.Variant != "" {
+		name = fmt.Sprintf("%s (%s)", v.Product.Name, v.Variant)
+	}
+	switch {
+	case v.DeletedAt != nil || v.Product.DeletedAt != nil:
+		return fmt.Sprintf("%s is no longer available", name)
+	case !v.Product.Published:
+		return fmt.Sprintf("%s is not published", name)
+	case v.Stock < qty:
+		return fmt.Sprintf("insufficient stock for %s", name)
+	}
+	return ""
+}
+
+func (s *orderService) QuoteOrder(ctx context.Context, req dto.CreateOrderRequest, customerID uint) (*dto.CheckoutQuoteResponse, error) {
+	c, err := s.prepareCheckout(ctx, req, customerID)
+	if err != nil {
+		return nil, err
+	}
+	o := toOrderResponse(*c.order)
+	errs := c.errors
+	if errs == nil {
+		errs = []string{}
+	}
+	options := make([]dto.ShippingOptionResponse, 0, len(c.shipping))
+	for _, opt := range c.shipping {
+		options = append(options, dto.ShippingOptionResponse{
+			MethodID:      opt.method.ID,
+			Name:          opt.method.Name,
+			EstimatedDays: opt.method.EstimatedDays,
+			Fee:           opt.fee,
+		})
+	}
+	return &dto.CheckoutQuoteResponse{
+		Items:            o.Items,
+		Subtotal:         o.Subtotal,
+		Discount:         o.Discount,
+		ShippingFee:      o.ShippingFee,
+		Tax:              o.Tax,
+		GrandTotal:       o.GrandTotal,
+		WalletAmount:     o.WalletAmount,
+		GiftCardAmount:   o.GiftCardAmount,
+		AmountDue:        o.AmountDue,
+		ShippingMethodID: c.order.ShippingMethodID,
+		ShippingOptions:  options,
+		TotalWeight:      c.order.TotalWeight,
+		DeliveryMode:     c.order.DeliveryMode,
+		Valid:            len(c.errors) == 0,
+		Errors:           errs,
+	}, nil
+}
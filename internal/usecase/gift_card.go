@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// giftCardCodeLength is the length of generated codes; 16 characters of a
+// 32 character alphabet make codes practically impossible to guess.
+const giftCardCodeLength = 16
+
+type GiftCardService interface {
+	IssueGiftCard(ctx context.Context, adminID uint, req dto.IssueGiftCardRequest) (*dto.GiftCardResponse, error)
+	GenerateGiftCards(ctx context.Context, adminID uint, req dto.BulkGiftCardRequest) (*dto.BulkGiftCardResponse, error)
+	ListGiftCards(ctx context.Context, q dto.GiftCardListQuery) (*dto.GiftCardListResponse, error)
+	GetGiftCard(ctx context.Context, id uint) (*dto.GiftCardResponse, error)
+	CheckBalance(ctx context.Context, code string) (*dto.GiftCardBalanceResponse, error)
+}
+
+type giftCardService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewGiftCardService(repo repository.Repository, logger *zap.Logger) GiftCardService {
+	return &giftCardService{repo: repo, logger: logger}
+}
+
+// IssueGiftCard issues a single card, with a generated code unless one is given.
+func (s *giftCardService) IssueGiftCard(ctx context.Context, adminID uint, req dto.IssueGiftCardRequest) (*dto.GiftCardResponse, error) {
+	if err := checkGiftCardExpiry(req.ExpiresAt); err != nil {
+		return nil, err
+	}
+	code := normalizeGiftCardCode(req.Code)
+	if code == "" {
+		var err error
+		if code, err = utils.GenerateCode(giftCardCodeLength); err != nil {
+			return nil, err
+		}
+	} else if _, err := s.repo.GiftCardRepo.GetGiftCardByCode(ctx, code); err == nil {
+		return nil, errors.New("gift card code already exists")
+	}
+
+	cards := []entity.GiftCard{newGiftCard(code, req.Amount, req.ExpiresAt, req.Note, adminID)}
+	if err := s.repo.GiftCardRepo.CreateGiftCards(ctx, cards); err != nil {
+		return nil, err
+	}
+	res := toGiftCardResponse(cards[0])
+	return &res, nil
+}
+
+// GenerateGiftCards generates a batch of cards with random codes, all or nothing.
+func (s *giftCardService) GenerateGiftCards(ctx context.Context, adminID uint, req dto.BulkGiftCardRequest) (*dto.BulkGiftCardResponse, error) {
+	if err := checkGiftCardExpiry(req.ExpiresAt); err != nil {
+		return nil, err
+	}
+	suffix, err := utils.GenerateCode(6)
+	if err != nil {
+		return nil, err
+	}
+	batchID := fmt.Sprintf("GCB-%s-%s", time.Now().Format("20060102"), suffix)
+
+	seen := make(map[string]bool, req.Count)
+	cards := make([]entity.GiftCard, 0, req.Count)
+	for len(cards) < req.Count {
+		code, err := utils.GenerateCode(giftCardCodeLength)
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		card := newGiftCard(code, req.Amount, req.ExpiresAt, req.Note, adminID)
+		card.BatchID = batchID
+		cards = append(cards, card)
+	}
+	if err := s.repo.GiftCardRepo.CreateGiftCards(ctx, cards); err != nil {
+		return nil, err
+	}
+
+	res := &dto.BulkGiftCardResponse{BatchID: batchID, Items: make([]dto.GiftCardResponse, 0, len(cards))}
+	for _, c := range cards {
+		res.Items = append(res.Items, toGiftCardResponse(c))
+	}
+	return res, nil
+}
+
+func (s *giftCardService) ListGiftCards(ctx context.Context, q dto.GiftCardListQuery) (*dto.GiftCardListResponse, error) {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	cards, total, err := s.repo.GiftCardRepo.ListGiftCards(ctx, q.Page, q.Limit, q.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.GiftCardResponse, 0, len(cards))
+	for _, c := range cards {
+		items = append(items, toGiftCardResponse(c))
+	}
+	return &dto.GiftCardListResponse{
+		Items:        items,
+		CurrentPage:  q.Page,
+		Limit:        q.Limit,
+		TotalPages:   int((total + int64(q.Limit) - 1) / int64(q.Limit)),
+		TotalRecords: total,
+	}, nil
+}
+
+// GetGiftCard returns a card with its redemption ledger.
+func (s *giftCardService) GetGiftCard(ctx context.Context, id uint) (*dto.GiftCardResponse, error) {
+	card, err := s.repo.GiftCardRepo.GetGiftCardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	redemptions, err := s.repo.GiftCardRepo.ListRedemptions(ctx, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := toGiftCardResponse(*card)
+	res.Redemptions = make([]dto.GiftCardRedemptionResponse, 0, len(redemptions))
+	for _, r := range redemptions {
+		res.Redemptions = append(res.Redemptions, dto.GiftCardRedemptionResponse{
+			OrderID:      r.OrderID,
+			Type:         r.Type,
+			Amount:       r.Amount,
+			BalanceAfter: r.BalanceAfter,
+			CreatedAt:    r.CreatedAt,
+		})
+	}
+	return &res, nil
+}
+
+// CheckBalance lets customers look up the balance of a code before checkout.
+func (s *giftCardService) CheckBalance(ctx context.Context, code string) (*dto.GiftCardBalanceResponse, error) {
+	card, err := s.repo.GiftCardRepo.GetGiftCardByCode(ctx, normalizeGiftCardCode(code))
+	if err != nil {
+		return nil, errors.New("invalid gift card")
+	}
+	return &dto.GiftCardBalanceResponse{Code: card.Code, Balance: card.Balance, ExpiresAt: card.ExpiresAt}, nil
+}
+
+func newGiftCard(code string, amount float64, expiresAt *time.Time, note string, adminID uint) entity.GiftCard {
+	amount = roundMoney(amount)
+	return entity.GiftCard{
+		Code:           code,
+		InitialBalance: amount,
+		Balance:        amount,
+		ExpiresAt:      expiresAt,
+		Note:           note,
+		IssuedBy:       fmt.Sprintf("admin:%d", adminID),
+	}
+}
+
+func checkGiftCardExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errors.New("expiry must be in the future")
+	}
+	return nil
+}
+
+// normalizeGiftCardCode makes codes case-insensitive and ignores the spaces
+// and dashes people add when typing them.
+func normalizeGiftCardCode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func toGiftCardResponse(g entity.GiftCard) dto.GiftCardResponse {
+	return dto.GiftCardResponse{
+		ID:             g.ID,
+		Code:           g.Code,
+		InitialBalance: g.InitialBalance,
+		Balance:        g.Balance,
+		ExpiresAt:      g.ExpiresAt,
+		BatchID:        g.BatchID,
+		Note:           g.Note,
+		IssuedBy:       g.IssuedBy,
+		CreatedAt:      g.CreatedAt,
+	}
+}
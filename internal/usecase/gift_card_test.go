@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+)
+
+// In-memory gift card store
+type memGiftCardRepo struct {
+	cards []entity.GiftCard
+}
+
+func (r *memGiftCardRepo) CreateGiftCards(ctx context.Context, cards []entity.GiftCard) error {
+	for i := range cards {
+		cards[i].ID = uint(len(r.cards) + 1)
+		r.cards = append(r.cards, cards[i])
+	}
+	return nil
+}
+
+func (r *memGiftCardRepo) GetGiftCardByID(ctx context.Context, id uint) (*entity.GiftCard, error) {
+	for _, c := range r.cards {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memGiftCardRepo) GetGiftCardByCode(ctx context.Context, code string) (*entity.GiftCard, error) {
+	for _, c := range r.cards {
+		if c.Code == code {
+			return &c, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memGiftCardRepo) ListGiftCards(ctx context.Context, page, limit int, batchID string) ([]entity.GiftCard, int64, error) {
+	return r.cards, int64(len(r.cards)), nil
+}
+
+func (r *memGiftCardRepo) ListRedemptions(ctx context.Context, giftCardID uint) ([]entity.GiftCardRedemption, error) {
+	return nil, nil
+}
+
+func TestCreateOrder_GiftCardSpentBeforeWallet(t *testing.T) {
+	repoVal := walletCheckoutRepo(250)
+	repoVal.GiftCardRepo = &memGiftCardRepo{cards: []entity.GiftCard{{Model: entity.Model{ID: 1}, Code: "ABCD2345EFGH6789", Balance: 60}}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+
+	code, amount := "abcd-2345-efgh-6789", 100.0
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", GiftCardCode: &code, WalletAmount: &amount}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.GiftCardAmount != 60 || res.WalletAmount != 40 || res.AmountDue != 0 || res.Status != entity.OrderStatusPaid || res.PaymentMethod != payment.MethodWallet {
+		t.Fatalf("expected 60 from the gift card and 40 from the wallet, got %+v", res)
+	}
+}
+
+func TestCreateOrder_GiftCardLeavesRestForProvider(t *testing.T) {
+	repoVal := walletCheckoutRepo(0)
+	repoVal.GiftCardRepo = &memGiftCardRepo{cards: []entity.GiftCard{{Model: entity.Model{ID: 1}, Code: "ABCD2345EFGH6789", Balance: 30}}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+
+	code := "ABCD2345EFGH6789"
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", GiftCardCode: &code}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.GiftCardAmount != 30 || res.AmountDue != 70 || res.Status != entity.OrderStatusCreated || res.PaymentMethod != "gopay" {
+		t.Fatalf("expected 70 left to pay with gopay, got %+v", res)
+	}
+}
+
+func TestCreateOrder_RejectsExpiredGiftCard(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	repoVal := walletCheckoutRepo(0)
+	repoVal.GiftCardRepo = &memGiftCardRepo{cards: []entity.GiftCard{{Model: entity.Model{ID: 1}, Code: "ABCD2345EFGH6789", Balance: 30, ExpiresAt: &expired}}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+
+	code := "ABCD2345EFGH6789"
+	_, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", GiftCardCode: &code}, 1)
+	if err == nil || err.Error() != "gift card has expired" {
+		t.Fatalf("expected expired gift card to be rejected, got %v", err)
+	}
+}
+
+func TestGenerateGiftCards_UniqueCodesInOneBatch(t *testing.T) {
+	cards := &memGiftCardRepo{}
+	svc := NewGiftCardService(repository.Repository{GiftCardRepo: cards}, zap.NewNop())
+
+	res, err := svc.GenerateGiftCards(context.Background(), 1, dto.BulkGiftCardRequest{Count: 50, Amount: 25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, c := range res.Items {
+		if len(c.Code) != giftCardCodeLength || seen[c.Code] || c.BatchID != res.BatchID || c.Balance != 25 || c.ID == 0 {
+			t.Fatalf("unexpected card in batch %s: %+v", res.BatchID, c)
+		}
+		seen[c.Code] = true
+	}
+	if len(seen) != 50 {
+		t.Fatalf("expected 50 cards, got %d", len(seen))
+	}
+}
+
+func TestIssueGiftCard_RejectsDuplicateCode(t *testing.T) {
+	cards := &memGiftCardRepo{}
+	svc := NewGiftCardService(repository.Repository{GiftCardRepo: cards}, zap.NewNop())
+	ctx := context.Background()
+
+	res, err := svc.IssueGiftCard(ctx, 1, dto.IssueGiftCardRequest{Code: "welcome-2025", Amount: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Code != "WELCOME2025" {
+		t.Fatalf("expected a normalized code, got %s", res.Code)
+	}
+	if _, err := svc.IssueGiftCard(ctx, 1, dto.IssueGiftCardRequest{Code: "Welcome 2025", Amount: 50}); err == nil {
+		t.Fatalf("expected the same code to be rejected")
+	}
+	past := time.Now().Add(-time.Minute)
+	if _, err := svc.IssueGiftCard(ctx, 1, dto.IssueGiftCardRequest{Amount: 50, ExpiresAt: &past}); err == nil {
+		t.Fatalf("expected an expiry in the past to be rejected")
+	}
+}
@@ -2,14 +2,34 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
 )
 
 type OrderService interface {
 	CreateOrder(ctx context.Context, req dto.CreateOrderRequest, customerID uint) (*dto.OrderResponse, error)
+	QuoteOrder(ctx context.Context, req dto.CreateOrderRequest, customerID uint) (*dto.CheckoutQuoteResponse, error)
 	GetOrderDetail(ctx context.Context, id uint, customerID uint) (*dto.OrderResponse, error)
+	GetOrderDetailByNumber(ctx context.Context, number string, customerID uint) (*dto.OrderResponse, error)
 	ListOrderHistory(ctx context.Context, customerID uint, limit, offset int) ([]dto.OrderResponse, int64, error)
 	GetCart(ctx context.Context, customerID uint) (*dto.CartResponse, error)
+	Reorder(ctx context.Context, orderID uint, customerID uint) (*dto.ReorderResponse, error)
+	ListOrders(ctx context.Context, q dto.AdminOrderListQuery) (*dto.AdminOrderListResponse, error)
+	UpdateOrderStatus(ctx context.Context, id uint, req dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error)
+	CreateShipment(ctx context.Context, orderID uint, req dto.CreateShipmentRequest) (*dto.OrderResponse, error)
+	UpdateShipmentStatus(ctx context.Context, orderID, shipmentID uint, req dto.UpdateShipmentStatusRequest) (*dto.OrderResponse, error)
+	PayOrder(ctx context.Context, orderID uint, customerID uint) (*dto.PaymentResponse, error)
+	GetPayment(ctx context.Context, orderID uint, customerID uint) (*dto.PaymentResponse, error)
+	RefundPayment(ctx context.Context, orderID uint, req dto.RefundPaymentRequest) (*dto.PaymentResponse, error)
+	CollectCOD(ctx context.Context, orderID uint, req dto.CollectCODRequest) (*dto.PaymentResponse, error)
+	VerifyPickup(ctx context.Context, req dto.VerifyPickupRequest) (*dto.OrderResponse, error)
+	CancelExpiredOrders(ctx context.Context) (int, error)
+	SyncShipmentTracking(ctx context.Context) (int, error)
+	HandlePaymentWebhook(ctx context.Context, method string, header http.Header, body []byte) error
+	ExportOrders(ctx context.Context, q dto.OrderExportQuery, w io.Writer) error
+	ExportPickList(ctx context.Context, q dto.FulfillmentQuery, w io.Writer) error
+	ExportPackingSlips(ctx context.Context, q dto.FulfillmentQuery, w io.Writer) error
 }
 
 // Implementation will be added later; this is a placeholder interface to wire handlers.
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"time"
+)
+
+// checkPaymentMethod rejects payment methods that are not enabled, and cash
+// on delivery for orders above the limit or customers who lost it by
+// cancelling too many COD orders.
+func (s *orderService) checkPaymentMethod(ctx context.Context, c *checkout, customerID uint) error {
+	method := c.order.PaymentMethod
+	if method != payment.MethodCOD {
+		if _, err := s.payments.Get(method); err != nil {
+			c.reject(err.Error())
+		}
+		return nil
+	}
+	if !s.payments.Allowed(method) {
+		c.reject(fmt.Sprintf("payment method %s is not supported", method))
+		return nil
+	}
+	if max := s.config.CODMaxOrderValue; max > 0 && amountDue(*c.order) > max {
+		c.reject(fmt.Sprintf("cash on delivery is only available for orders up to %.2f", max))
+	}
+	if max := s.config.CODMaxCancelled; max > 0 {
+		n, err := s.repo.OrderRepo.CountCustomerOrders(ctx, customerID, payment.MethodCOD, entity.OrderStatusCancelled)
+		if err != nil {
+			return err
+		}
+		if n >= int64(max) {
+			c.reject("cash on delivery is not available for your account")
+		}
+	}
+	return nil
+}
+
+// CollectCOD records the cash the courier collected for a COD order as its payment.
+func (s *orderService) CollectCOD(ctx context.Context, orderID uint, req dto.CollectCODRequest) (*dto.PaymentResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.PaymentMethod != payment.MethodCOD {
+		return nil, errors.New("order is not cash on delivery")
+	}
+	if o.Status == entity.OrderStatusCancelled {
+		return nil, errors.New("order is cancelled")
+	}
+	if p, err := s.repo.PaymentRepo.GetLatestPaymentByOrder(ctx, o.ID); err == nil && p.Status == string(payment.StatusPaid) {
+		return nil, errors.New("cash was already collected")
+	}
+	if roundMoney(req.Amount) != amountDue(*o) {
+		return nil, fmt.Errorf("collected %.2f but the amount due is %.2f", req.Amount, amountDue(*o))
+	}
+
+	now := time.Now()
+	p := &entity.Payment{
+		OrderID:     o.ID,
+		Method:      payment.MethodCOD,
+		ChargeID:    fmt.Sprintf("cod-%d", o.ID),
+		Amount:      req.Amount,
+		Status:      string(payment.StatusPaid),
+		PaidAt:      &now,
+		CollectedBy: req.CollectedBy,
+	}
+	if err := s.repo.PaymentRepo.CreatePayment(ctx, p); err != nil {
+		return nil, err
+	}
+	res := toPaymentResponse(*p)
+	return &res, nil
+}
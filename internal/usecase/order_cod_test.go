@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+)
+
+// Order repo reporting a fixed number of cancelled COD orders
+type codHistoryOrderRepo struct {
+	simpleOrderRepo
+	cancelledCOD int64
+}
+
+func (r *codHistoryOrderRepo) CountCustomerOrders(ctx context.Context, customerID uint, paymentMethod, status string) (int64, error) {
+	if paymentMethod == payment.MethodCOD && status == entity.OrderStatusCancelled {
+		return r.cancelledCOD, nil
+	}
+	return 0, nil
+}
+
+func codTestService(unitPrice float64, cancelledCOD int64) *orderService {
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: unitPrice}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &codHistoryOrderRepo{cancelledCOD: cancelledCOD}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	config := utils.Configuration{CODMaxOrderValue: 500, CODMaxCancelled: 2}
+	return &orderService{repo: repoVal, logger: zap.NewNop(), config: config, payments: payment.NewRegistry([]string{payment.MethodCOD})}
+}
+
+func TestCreateOrder_CODGoesStraightToProcessing(t *testing.T) {
+	svc := codTestService(100, 0)
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: payment.MethodCOD}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusProcessing || res.PaymentExpiresAt != nil {
+		t.Fatalf("expected processing order without payment deadline, got %s %v", res.Status, res.PaymentExpiresAt)
+	}
+}
+
+func TestCreateOrder_CODLimits(t *testing.T) {
+	_, err := codTestService(600, 0).CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: payment.MethodCOD}, 1)
+	if err == nil || !strings.Contains(err.Error(), "only available for orders up to 500.00") {
+		t.Fatalf("expected order value limit, got %v", err)
+	}
+	_, err = codTestService(100, 2).CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: payment.MethodCOD}, 1)
+	if err == nil || err.Error() != "cash on delivery is not available for your account" {
+		t.Fatalf("expected customer to lose cod, got %v", err)
+	}
+}
+
+func TestCollectCOD_RecordsPaymentOnce(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, PaymentMethod: payment.MethodCOD, GrandTotal: 111, Status: entity.OrderStatusShipped}
+	svc, _, repo := paymentTestService(order)
+	ctx := context.Background()
+
+	if _, err := svc.CollectCOD(ctx, 1, dto.CollectCODRequest{Amount: 100, CollectedBy: "courier-7"}); err == nil {
+		t.Fatalf("expected amount mismatch error")
+	}
+	res, err := svc.CollectCOD(ctx, 1, dto.CollectCODRequest{Amount: 111, CollectedBy: "courier-7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != string(payment.StatusPaid) || res.CollectedBy != "courier-7" || len(repo.payments) != 1 {
+		t.Fatalf("unexpected payment %+v", res)
+	}
+	if _, err := svc.CollectCOD(ctx, 1, dto.CollectCODRequest{Amount: 111, CollectedBy: "courier-7"}); err == nil {
+		t.Fatalf("cash must not be collected twice")
+	}
+}
+
+func TestUpdateOrderStatus_CancellingCollectedPickupRefundsToWallet(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 3, PaymentMethod: payment.MethodCOD, GrandTotal: 111,
+		Status: entity.OrderStatusReadyForPickup, DeliveryMode: entity.DeliveryModePickup}
+	svc, _, repo := paymentTestService(order)
+	ctx := context.Background()
+
+	if _, err := svc.CollectCOD(ctx, 1, dto.CollectCODRequest{Amount: 111, CollectedBy: "store"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, err := svc.UpdateOrderStatus(ctx, 1, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusCancelled})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusCancelled || repo.payments[0].Status != string(payment.StatusRefunded) {
+		t.Fatalf("expected a cancelled order and a refunded payment, got %s / %s", res.Status, repo.payments[0].Status)
+	}
+	if len(repo.wallet) != 1 || repo.wallet[0].CustomerID != 3 || repo.wallet[0].Amount != 111 {
+		t.Fatalf("expected the cash to be credited to the wallet, got %+v", repo.wallet)
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPaymentDeadline = 24 * time.Hour
+	expiryBatchSize        = 100
+)
+
+// paymentDeadline is how long a customer has to pay with method.
+func (s *orderService) paymentDeadline(method string) time.Duration {
+	if d, ok := s.config.PaymentDeadlines[method]; ok && d > 0 {
+		return d
+	}
+	if s.config.PaymentDeadline > 0 {
+		return s.config.PaymentDeadline
+	}
+	return defaultPaymentDeadline
+}
+
+// CancelExpiredOrders cancels unpaid orders whose payment deadline passed,
+// putting their stock and voucher usage back. It returns how many it cancelled.
+func (s *orderService) CancelExpiredOrders(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		orders, err := s.repo.OrderRepo.ListExpiredUnpaidOrders(ctx, time.Now(), expiryBatchSize)
+		if err != nil {
+			return total, err
+		}
+		n := 0
+		for i := range orders {
+			o := &orders[i]
+			cancelled, err := s.repo.OrderRepo.CancelOrder(ctx, o)
+			if err != nil {
+				return total, err
+			}
+			if !cancelled {
+				// paid or cancelled in the meantime
+				continue
+			}
+			n++
+			o.Status = entity.OrderStatusCancelled
+			s.logger.Info("cancelled unpaid order", zap.Uint("order_id", o.ID), zap.Timep("payment_expires_at", o.PaymentExpiresAt))
+			s.notifier.Notify(*o, orderEventCancelled)
+		}
+		total += n
+		if len(orders) < expiryBatchSize || n == 0 {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+)
+
+// Order repo holding expired orders; one of them gets paid concurrently
+type expiringOrderRepo struct {
+	simpleOrderRepo
+	expired   []entity.Order
+	paidID    uint
+	cancelled []uint
+}
+
+func (r *expiringOrderRepo) ListExpiredUnpaidOrders(ctx context.Context, now time.Time, limit int) ([]entity.Order, error) {
+	var out []entity.Order
+	for _, o := range r.expired {
+		if o.PaymentExpiresAt.Before(now) && o.Status == entity.OrderStatusCreated {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *expiringOrderRepo) CancelOrder(ctx context.Context, o *entity.Order) (bool, error) {
+	for i := range r.expired {
+		if r.expired[i].ID == o.ID {
+			if o.ID == r.paidID {
+				return false, nil
+			}
+			r.expired[i].Status = entity.OrderStatusCancelled
+			r.cancelled = append(r.cancelled, o.ID)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCancelExpiredOrders_SkipsOrdersPaidMeanwhile(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	repo := &expiringOrderRepo{paidID: 2, expired: []entity.Order{
+		{Model: entity.Model{ID: 1}, Status: entity.OrderStatusCreated, PaymentExpiresAt: &past, ShippingEmail: "a@example.com"},
+		{Model: entity.Model{ID: 2}, Status: entity.OrderStatusCreated, PaymentExpiresAt: &past},
+	}}
+	sender := &chanEmailSender{sent: make(chan sentEmail, 2)}
+	svc := &orderService{repo: repository.Repository{OrderRepo: repo}, logger: zap.NewNop(), notifier: newOrderNotifier(sender, zap.NewNop())}
+
+	n, err := svc.CancelExpiredOrders(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(repo.cancelled) != 1 || repo.cancelled[0] != 1 {
+		t.Fatalf("expected only order 1 cancelled, got %d %v", n, repo.cancelled)
+	}
+	if m := waitEmail(t, sender); m.to != "a@example.com" {
+		t.Fatalf("expected a cancellation email, got %+v", m)
+	}
+}
+
+func TestCreateOrder_SetsPaymentDeadlinePerMethod(t *testing.T) {
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: 100}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	config := utils.Configuration{PaymentDeadline: 24 * time.Hour, PaymentDeadlines: map[string]time.Duration{"gopay": 15 * time.Minute}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), config: config, payments: testPayments()}
+
+	before := time.Now()
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.PaymentExpiresAt == nil || res.PaymentExpiresAt.Sub(before) < 15*time.Minute || res.PaymentExpiresAt.Sub(before) > 16*time.Minute {
+		t.Fatalf("expected a 15 minute deadline, got %v", res.PaymentExpiresAt)
+	}
+}
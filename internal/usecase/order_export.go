@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"strconv"
+	"time"
+)
+
+var orderExportHeader = []string{
+	"order_number", "order_id", "created_at", "status", "customer_id", "payment_method",
+	"voucher_code", "promotion_id", "subtotal", "discount", "shipping_fee", "tax", "grand_total",
+	"sku", "product_name", "variant_name", "quantity", "unit_price", "line_total",
+}
+
+// flush rows to the client every exportFlushEvery lines
+const exportFlushEvery = 500
+
+func orderExportCells(r repository.OrderExportRow) []any {
+	var promotionID any
+	if r.PromotionID != nil {
+		promotionID = *r.PromotionID
+	}
+	return []any{
+		utils.Deref(r.OrderNumber), r.OrderID, r.CreatedAt, r.Status, r.CustomerID, r.PaymentMethod,
+		utils.Deref(r.VoucherCode), promotionID, r.Subtotal, r.Discount, r.ShippingFee, r.Tax, r.GrandTotal,
+		r.SKU, r.ProductName, r.VariantName, r.Quantity, r.UnitPrice, roundMoney(float64(r.Quantity) * r.UnitPrice),
+	}
+}
+
+// ExportOrders streams the order lines matching q to w as CSV or XLSX.
+// Nothing is written to w when q is invalid.
+func (s *orderService) ExportOrders(ctx context.Context, q dto.OrderExportQuery, w io.Writer) error {
+	if q.Format == "" {
+		q.Format = "csv"
+	}
+	if q.Format != "csv" && q.Format != "xlsx" {
+		return errors.New("format must be csv or xlsx")
+	}
+	from, to := exportRange(q)
+	if !from.Before(to) {
+		return errors.New("from must be before to")
+	}
+
+	var writeRow func([]any) error
+	var flush, finish func() error
+	switch q.Format {
+	case "xlsx":
+		xw, err := utils.NewXLSXWriter(w, "Orders")
+		if err != nil {
+			return err
+		}
+		writeRow, flush, finish = xw.WriteRow, xw.Flush, xw.Close
+	default:
+		cw := csv.NewWriter(w)
+		writeRow = func(cells []any) error { return cw.Write(csvCells(cells)) }
+		flush = func() error { cw.Flush(); return cw.Error() }
+		finish = flush
+	}
+
+	header := make([]any, len(orderExportHeader))
+	for i, h := range orderExportHeader {
+		header[i] = h
+	}
+	if err := writeRow(header); err != nil {
+		return err
+	}
+
+	n := 0
+	err := s.repo.OrderRepo.StreamOrderLines(ctx, from, to, q.Status, func(r repository.OrderExportRow) error {
+		if err := writeRow(orderExportCells(r)); err != nil {
+			return err
+		}
+		n++
+		if n%exportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			if f, ok := w.(interface{ Flush() }); ok {
+				f.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return finish()
+}
+
+// exportRange turns the inclusive from/to dates into a [from, to) range,
+// defaulting to the current month.
+func exportRange(q dto.OrderExportQuery) (time.Time, time.Time) {
+	now := time.Now()
+	from := q.From
+	if from.IsZero() {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	}
+	to := q.To
+	if to.IsZero() {
+		return from, from.AddDate(0, 1, 0)
+	}
+	return from, to.AddDate(0, 0, 1)
+}
+
+func csvCells(cells []any) []string {
+	out := make([]string, len(cells))
+	for i, c := range cells {
+		switch v := c.(type) {
+		case nil:
+			out[i] = ""
+		case string:
+			out[i] = v
+		case int:
+			out[i] = strconv.Itoa(v)
+		case uint:
+			out[i] = strconv.FormatUint(uint64(v), 10)
+		case float64:
+			out[i] = strconv.FormatFloat(v, 'f', 2, 64)
+		case time.Time:
+			out[i] = v.Format(time.RFC3339)
+		}
+	}
+	return out
+}
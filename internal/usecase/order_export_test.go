@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+func exportFixture() *orderService {
+	number, code, promoID := "ORD-20250105-000001", "PROMO10", uint(1)
+	lines := []repository.OrderExportRow{
+		{OrderID: 1, OrderNumber: &number, CreatedAt: time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), Status: "paid", CustomerID: 4, PaymentMethod: "gopay", VoucherCode: &code, PromotionID: &promoID,
+			Subtotal: 200, Discount: 20, GrandTotal: 180, SKU: "HD-01", ProductName: "Hoodie & Co", VariantName: "XL", Quantity: 2, UnitPrice: 100},
+	}
+	return &orderService{repo: repository.Repository{OrderRepo: &simpleOrderRepo{lines: lines}}, logger: zap.NewNop()}
+}
+
+func TestExportOrders_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	q := dto.OrderExportQuery{From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)}
+	if err := exportFixture().ExportOrders(context.Background(), q, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(records) != 2 || records[0][0] != "order_number" {
+		t.Fatalf("unexpected csv: %v", records)
+	}
+	row := strings.Join(records[1], ",")
+	for _, want := range []string{"ORD-20250105-000001", "gopay", "PROMO10", "20.00", "200.00"} {
+		if !strings.Contains(row, want) {
+			t.Fatalf("csv row missing %q: %s", want, row)
+		}
+	}
+}
+
+func TestExportOrders_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportFixture().ExportOrders(context.Background(), dto.OrderExportQuery{Format: "xlsx"}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid xlsx archive: %v", err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(b)
+		}
+	}
+	if !strings.Contains(sheet, "Hoodie &amp; Co") || !strings.Contains(sheet, `<row r="2">`) {
+		t.Fatalf("unexpected sheet content: %s", sheet)
+	}
+}
+
+func TestExportOrders_RejectsInvalidQueryWithoutWriting(t *testing.T) {
+	var buf bytes.Buffer
+	q := dto.OrderExportQuery{Format: "pdf"}
+	if err := exportFixture().ExportOrders(context.Background(), q, &buf); err == nil || buf.Len() != 0 {
+		t.Fatalf("expected validation error and empty output, got %v / %d bytes", err, buf.Len())
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pickListLine is the total quantity of one variant across a batch of orders.
+type pickListLine struct {
+	ProductVariantID uint
+	SKU              string
+	ProductName      string
+	VariantName      string
+	Quantity         int
+	Orders           []string
+}
+
+// fulfillmentOrders loads the selected orders, all of which must be in processing.
+func (s *orderService) fulfillmentOrders(ctx context.Context, q dto.FulfillmentQuery) ([]entity.Order, error) {
+	if q.Format == "" {
+		q.Format = "pdf"
+	}
+	if q.Format != "pdf" && q.Format != "csv" {
+		return nil, errors.New("format must be pdf or csv")
+	}
+	if len(q.OrderIDs) == 0 {
+		return nil, errors.New("select at least one order")
+	}
+	ids := make([]uint, 0, len(q.OrderIDs))
+	seen := map[uint]bool{}
+	for _, id := range q.OrderIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	orders, err := s.repo.OrderRepo.GetOrdersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := map[uint]bool{}
+	for _, o := range orders {
+		found[o.ID] = true
+		if o.Status != entity.OrderStatusProcessing {
+			return nil, fmt.Errorf("order %s is %s, not processing", orderLabel(o), o.Status)
+		}
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("order %d not found", id)
+		}
+	}
+	return orders, nil
+}
+
+// buildPickList groups the lines of orders by variant, ordered by SKU.
+func buildPickList(orders []entity.Order) []pickListLine {
+	byVariant := map[uint]*pickListLine{}
+	for _, o := range orders {
+		for _, it := range o.Items {
+			l, ok := byVariant[it.ProductVariantID]
+			if !ok {
+				l = &pickListLine{ProductVariantID: it.ProductVariantID, SKU: it.SKU, ProductName: it.ProductName, VariantName: it.VariantName}
+				byVariant[it.ProductVariantID] = l
+			}
+			l.Quantity += it.Quantity
+			if n := len(l.Orders); n == 0 || l.Orders[n-1] != orderLabel(o) {
+				l.Orders = append(l.Orders, orderLabel(o))
+			}
+		}
+	}
+	lines := make([]pickListLine, 0, len(byVariant))
+	for _, l := range byVariant {
+		lines = append(lines, *l)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].SKU != lines[j].SKU {
+			return lines[i].SKU < lines[j].SKU
+		}
+		return lines[i].ProductVariantID < lines[j].ProductVariantID
+	})
+	return lines
+}
+
+// ExportPickList writes the aggregated pick list for the selected orders.
+func (s *orderService) ExportPickList(ctx context.Context, q dto.FulfillmentQuery, w io.Writer) error {
+	orders, err := s.fulfillmentOrders(ctx, q)
+	if err != nil {
+		return err
+	}
+	lines := buildPickList(orders)
+
+	if q.Format == "csv" {
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"product_variant_id", "sku", "product_name", "variant_name", "quantity", "orders"})
+		for _, l := range lines {
+			_ = cw.Write([]string{strconv.FormatUint(uint64(l.ProductVariantID), 10), l.SKU, l.ProductName, l.VariantName,
+				strconv.Itoa(l.Quantity), strings.Join(l.Orders, " ")})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	page := []string{
+		"PICK LIST",
+		fmt.Sprintf("Generated %s - %d orders", time.Now().Format("2006-01-02 15:04"), len(orders)),
+		"",
+		fmt.Sprintf("%-16s %-44s %5s", "SKU", "ITEM", "QTY"),
+		strings.Repeat("-", 67),
+	}
+	for _, l := range lines {
+		page = append(page, fmt.Sprintf("%-16s %-44s %5d", truncate(l.SKU, 16), truncate(itemLabel(l.ProductName, l.VariantName), 44), l.Quantity),
+			"    orders: "+strings.Join(l.Orders, ", "))
+	}
+	doc := utils.NewPDFDocument()
+	doc.AddPage(page)
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// ExportPackingSlips writes one packing slip per selected order.
+func (s *orderService) ExportPackingSlips(ctx context.Context, q dto.FulfillmentQuery, w io.Writer) error {
+	orders, err := s.fulfillmentOrders(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	if q.Format == "csv" {
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"order_number", "shipping_name", "shipping_address", "note", "sku", "product_name", "variant_name", "quantity"})
+		for _, o := range orders {
+			for _, it := range o.Items {
+				_ = cw.Write([]string{orderLabel(o), o.ShippingName, o.ShippingAddress, o.Note, it.SKU, it.ProductName, it.VariantName, strconv.Itoa(it.Quantity)})
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	doc := utils.NewPDFDocument()
+	for _, o := range orders {
+		heading := "Ship to:"
+		if o.DeliveryMode == entity.DeliveryModePickup {
+			heading = "Collect at " + o.ShippingAddress + " by:"
+		}
+		page := []string{
+			"PACKING SLIP",
+			"Order: " + orderLabel(o),
+			"Date:  " + o.CreatedAt.Format("2006-01-02"),
+			"",
+			heading,
+			"  " + o.ShippingName,
+		}
+		if o.DeliveryMode != entity.DeliveryModePickup {
+			page = append(page, "  "+o.ShippingAddress)
+		}
+		if o.ShippingPhone != "" {
+			page = append(page, "  "+o.ShippingPhone)
+		}
+		if o.Note != "" {
+			page = append(page, "", "Note: "+o.Note)
+		}
+		page = append(page, "",
+			fmt.Sprintf("%-16s %-44s %5s", "SKU", "ITEM", "QTY"),
+			strings.Repeat("-", 67))
+		for _, it := range o.Items {
+			page = append(page, fmt.Sprintf("%-16s %-44s %5d", truncate(it.SKU, 16), truncate(itemLabel(it.ProductName, it.VariantName), 44), it.Quantity))
+		}
+		doc.AddPage(page)
+	}
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+func orderLabel(o entity.Order) string {
+	if o.OrderNumber != nil && *o.OrderNumber != "" {
+		return *o.OrderNumber
+	}
+	return "#" + strconv.FormatUint(uint64(o.ID), 10)
+}
+
+func itemLabel(product, variant string) string {
+	if variant == "" {
+		return product
+	}
+	return product + " (" + variant + ")"
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "~"
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+// Order repo serving a fixed batch of orders
+type batchOrderRepo struct {
+	simpleOrderRepo
+	orders []entity.Order
+}
+
+func (r *batchOrderRepo) GetOrdersByIDs(ctx context.Context, ids []uint) ([]entity.Order, error) {
+	var out []entity.Order
+	for _, o := range r.orders {
+		for _, id := range ids {
+			if o.ID == id {
+				out = append(out, o)
+			}
+		}
+	}
+	return out, nil
+}
+
+func fulfillmentTestService() *orderService {
+	n1, n2 := "ORD-20250101-000001", "ORD-20250101-000002"
+	orders := []entity.Order{
+		{Model: entity.Model{ID: 1}, OrderNumber: &n1, ShippingName: "Zahra", ShippingAddress: "Jl. Kebangsaan No.10", Status: entity.OrderStatusProcessing,
+			Items: []entity.OrderItem{{ProductVariantID: 7, SKU: "TS-RED-M", ProductName: "T-Shirt", VariantName: "Red M", Quantity: 2}}},
+		{Model: entity.Model{ID: 2}, OrderNumber: &n2, ShippingName: "Budi", ShippingAddress: "Jl. Merdeka 1", Status: entity.OrderStatusProcessing,
+			Items: []entity.OrderItem{
+				{ProductVariantID: 7, SKU: "TS-RED-M", ProductName: "T-Shirt", VariantName: "Red M", Quantity: 1},
+				{ProductVariantID: 3, SKU: "HD-XL", ProductName: "Hoodie", VariantName: "XL", Quantity: 1},
+			}},
+		{Model: entity.Model{ID: 3}, Status: entity.OrderStatusCreated},
+	}
+	repoVal := repository.Repository{OrderRepo: &batchOrderRepo{orders: orders}}
+	return &orderService{repo: repoVal, logger: zap.NewNop()}
+}
+
+func TestExportPickList_AggregatesByVariant(t *testing.T) {
+	svc := fulfillmentTestService()
+	var buf bytes.Buffer
+	if err := svc.ExportPickList(context.Background(), dto.FulfillmentQuery{OrderIDs: []uint{1, 2}, Format: "csv"}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 variants, got %v", rows)
+	}
+	if rows[1][1] != "HD-XL" || rows[1][4] != "1" {
+		t.Fatalf("unexpected first line %v", rows[1])
+	}
+	if rows[2][1] != "TS-RED-M" || rows[2][4] != "3" || rows[2][5] != "ORD-20250101-000001 ORD-20250101-000002" {
+		t.Fatalf("expected 3 t-shirts across both orders, got %v", rows[2])
+	}
+}
+
+func TestExportPackingSlips_OnePagePerOrder(t *testing.T) {
+	svc := fulfillmentTestService()
+	var buf bytes.Buffer
+	if err := svc.ExportPackingSlips(context.Background(), dto.FulfillmentQuery{OrderIDs: []uint{1, 2}}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-") || !strings.Contains(out, "/Count 2") {
+		t.Fatalf("expected a 2 page pdf, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Jl. Merdeka 1") {
+		t.Fatalf("packing slip missing shipping address")
+	}
+}
+
+func TestExportPickList_RejectsOrdersNotProcessing(t *testing.T) {
+	svc := fulfillmentTestService()
+	var buf bytes.Buffer
+	err := svc.ExportPickList(context.Background(), dto.FulfillmentQuery{OrderIDs: []uint{1, 3}}, &buf)
+	if err == nil || !strings.Contains(err.Error(), "not processing") {
+		t.Fatalf("expected not processing error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("nothing must be written on error")
+	}
+	if err := svc.ExportPickList(context.Background(), dto.FulfillmentQuery{OrderIDs: []uint{9}}, &buf); err == nil {
+		t.Fatalf("expected error for unknown order")
+	}
+}
@@ -3,85 +3,72 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
 	"project-app-ecommerce-golang-tim-1/internal/data/repository"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
 	"time"
 )
 
 type orderService struct {
-	repo   repository.Repository
-	logger *zap.Logger
+	repo     repository.Repository
+	logger   *zap.Logger
+	config   utils.Configuration
+	pricer   orderPricer
+	notifier *orderNotifier
+	payments *payment.Registry
+	carriers *shipping.Registry
 }
 
-func NewOrderService(repo repository.Repository, logger *zap.Logger) OrderService {
-	return &orderService{repo: repo, logger: logger}
+func NewOrderService(repo repository.Repository, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender, payments *payment.Registry, carriers *shipping.Registry) OrderService {
+	return &orderService{
+		repo:     repo,
+		logger:   logger,
+		config:   config,
+		pricer:   newOrderPricer(config.TaxRate),
+		notifier: newOrderNotifier(emailSender, logger),
+		payments: payments,
+		carriers: carriers,
+	}
 }
 
 func (s *orderService) CreateOrder(ctx context.Context, req dto.CreateOrderRequest, customerID uint) (*dto.OrderResponse, error) {
-	// get cart
-	cart, err := s.repo.CartRepo.GetCartByCustomer(ctx, customerID)
+	c, err := s.prepareCheckout(ctx, req, customerID)
 	if err != nil {
 		return nil, err
 	}
-	if len(cart.Items) == 0 {
-		return nil, errors.New("cart is empty")
-	}
-
-	// calculate total
-	var total float64
-	items := make([]entity.OrderItem, 0, len(cart.Items))
-	for _, it := range cart.Items {
-		total += float64(it.Quantity) * it.UnitPrice
-		items = append(items, entity.OrderItem{ProductVariantID: it.ProductVariantID, Quantity: it.Quantity, UnitPrice: it.UnitPrice})
+	if err := c.err(); err != nil {
+		return nil, err
 	}
+	order := c.order
 
-	// prepare order
-	order := &entity.Order{
-		CustomerID:    customerID,
-		AddressID:     req.AddressID,
-		Note:          "",
-		PaymentMethod: req.PaymentMethod,
-		Status:        "created",
-		Items:         items,
+	number, err := s.nextOrderNumber(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	// apply voucher if present
-	var discount float64
-	if req.VoucherCode != nil && *req.VoucherCode != "" {
-		promo, err := s.repo.PromotionRepo.GetByVoucherCode(ctx, *req.VoucherCode)
+	order.OrderNumber = &number
+	if order.DeliveryMode == entity.DeliveryModePickup {
+		code, err := utils.GenerateCode(pickupCodeLength)
 		if err != nil {
-			return nil, errors.New("invalid voucher")
-		}
-		now := time.Now()
-		if !promo.Published || promo.StartDate.After(now) || promo.EndDate.Before(now) {
-			return nil, errors.New("voucher not active")
-		}
-
-		// enforce usage limit
-		if promo.UsageLimit <= 0 {
-			return nil, errors.New("voucher usage limit exceeded")
-		}
-		// compute discount based on type
-		if promo.Type == "percentage" {
-			discount = total * promo.Discount / 100.0
-		} else {
-			// assume fixed amount
-			discount = promo.Discount
-		}
-		if discount < 0 {
-			discount = 0
+			return nil, err
 		}
-		order.VoucherCode = req.VoucherCode
-		order.Discount = discount
-		// store promotion id for audit
-		order.PromotionID = &promo.ID
+		order.PickupCode = &code
 	}
-
-	totalAfter := total - discount
-	if totalAfter < 0 {
-		totalAfter = 0
+	switch order.PaymentMethod {
+	case payment.MethodWallet, payment.MethodGiftCard:
+		// paid in full from store credit when the order is written
+		order.Status = entity.OrderStatusPaid
+	case payment.MethodCOD:
+		// paid to the courier on delivery, so there is no payment to wait for
+		order.Status = entity.OrderStatusProcessing
+	default:
+		expires := time.Now().Add(s.paymentDeadline(order.PaymentMethod))
+		order.PaymentExpiresAt = &expires
 	}
 
 	// decrement promotion usage if applicable (do it before creating order to avoid races)
@@ -91,24 +78,28 @@ func (s *orderService) CreateOrder(ctx context.Context, req dto.CreateOrderReque
 		}
 	}
 
+	// stock, wallet and gift card balances roll back with the order; the
+	// voucher use was taken separately and has to be given back
 	if err := s.repo.OrderRepo.CreateOrder(ctx, order); err != nil {
-		// try to rollback usage decrement is not possible here; log and return error
-		s.logger.Error("failed to create order after decrementing promotion", zap.Error(err))
+		if order.PromotionID != nil {
+			if rerr := s.repo.PromotionRepo.RestoreUsage(ctx, *order.PromotionID); rerr != nil {
+				s.logger.Error("failed to restore promotion usage", zap.Uint("promotion_id", *order.PromotionID), zap.Error(rerr))
+			}
+		}
 		return nil, err
 	}
 
-	// clear cart
-	if err := s.repo.CartRepo.ClearCart(ctx, customerID); err != nil {
-		s.logger.Warn("failed to clear cart", zap.Error(err))
+	// clear cart; buy-now orders never touched it
+	if req.BuyNow == nil {
+		if err := s.repo.CartRepo.ClearCart(ctx, customerID); err != nil {
+			s.logger.Warn("failed to clear cart", zap.Error(err))
+		}
 	}
 
-	// build response
-	respItems := make([]dto.OrderItemDTO, 0, len(order.Items))
-	for _, it := range order.Items {
-		respItems = append(respItems, dto.OrderItemDTO{ProductVariantID: it.ProductVariantID, Quantity: it.Quantity, UnitPrice: it.UnitPrice})
-	}
+	s.notifier.Notify(*order, orderEventPlaced)
 
-	return &dto.OrderResponse{ID: order.ID, Items: respItems, Total: totalAfter, Status: order.Status}, nil
+	res := toOrderResponse(*order)
+	return &res, nil
 }
 
 func (s *orderService) GetOrderDetail(ctx context.Context, id uint, customerID uint) (*dto.OrderResponse, error) {
@@ -119,13 +110,21 @@ func (s *orderService) GetOrderDetail(ctx context.Context, id uint, customerID u
 	if o.CustomerID != customerID {
 		return nil, errors.New("not allowed")
 	}
-	respItems := make([]dto.OrderItemDTO, 0, len(o.Items))
-	var total float64
-	for _, it := range o.Items {
-		respItems = append(respItems, dto.OrderItemDTO{ProductVariantID: it.ProductVariantID, Quantity: it.Quantity, UnitPrice: it.UnitPrice})
-		total += float64(it.Quantity) * it.UnitPrice
+	res := toOrderResponse(*o)
+	return &res, nil
+}
+
+// GetOrderDetailByNumber serves customer-facing URLs that use the order number.
+func (s *orderService) GetOrderDetailByNumber(ctx context.Context, number string, customerID uint) (*dto.OrderResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
 	}
-	return &dto.OrderResponse{ID: o.ID, Items: respItems, Total: total, Status: o.Status}, nil
+	if o.CustomerID != customerID {
+		return nil, errors.New("not allowed")
+	}
+	res := toOrderResponse(*o)
+	return &res, nil
 }
 
 func (s *orderService) ListOrderHistory(ctx context.Context, customerID uint, limit, offset int) ([]dto.OrderResponse, int64, error) {
@@ -135,17 +134,108 @@ func (s *orderService) ListOrderHistory(ctx context.Context, customerID uint, li
 	}
 	res := make([]dto.OrderResponse, 0, len(orders))
 	for _, o := range orders {
-		var totalF float64
-		items := make([]dto.OrderItemDTO, 0, len(o.Items))
-		for _, it := range o.Items {
-			items = append(items, dto.OrderItemDTO{ProductVariantID: it.ProductVariantID, Quantity: it.Quantity, UnitPrice: it.UnitPrice})
-			totalF += float64(it.Quantity) * it.UnitPrice
-		}
-		res = append(res, dto.OrderResponse{ID: o.ID, Items: items, Total: totalF, Status: o.Status})
+		res = append(res, toOrderResponse(o))
 	}
 	return res, total, nil
 }
 
+// ListOrders lists all orders for admins, searchable by order number or name.
+func (s *orderService) ListOrders(ctx context.Context, q dto.AdminOrderListQuery) (*dto.AdminOrderListResponse, error) {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+
+	orders, total, err := s.repo.OrderRepo.ListOrders(ctx, q.Page, q.Limit, q.Search, q.Status)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.OrderResponse, 0, len(orders))
+	for _, o := range orders {
+		items = append(items, toOrderResponse(o))
+	}
+	totalPages := int((total + int64(q.Limit) - 1) / int64(q.Limit))
+
+	return &dto.AdminOrderListResponse{
+		Items:        items,
+		CurrentPage:  q.Page,
+		Limit:        q.Limit,
+		TotalPages:   totalPages,
+		TotalRecords: total,
+	}, nil
+}
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Orders only become paid through a verified payment, and cancelling a paid
+// order refunds its payment. Shipped and delivered
+// are otherwise derived from shipments; moving an order to shipped here
+// ships everything left in a single shipment. Pickup orders become picked up
+// only through VerifyPickup.
+var orderTransitions = map[string][]string{
+	entity.OrderStatusCreated:          {entity.OrderStatusCancelled},
+	entity.OrderStatusPaid:             {entity.OrderStatusProcessing, entity.OrderStatusCancelled},
+	entity.OrderStatusProcessing:       {entity.OrderStatusShipped, entity.OrderStatusReadyForPickup, entity.OrderStatusCancelled},
+	entity.OrderStatusPartiallyShipped: {entity.OrderStatusShipped},
+	entity.OrderStatusReadyForPickup:   {entity.OrderStatusCancelled},
+}
+
+// UpdateOrderStatus moves an order through its lifecycle and notifies the customer.
+func (s *orderService) UpdateOrderStatus(ctx context.Context, id uint, req dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, next := range orderTransitions[o.Status] {
+		if next == req.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("cannot change order status from %s to %s", o.Status, req.Status)
+	}
+	switch req.Status {
+	case entity.OrderStatusShipped:
+		return s.CreateShipment(ctx, o.ID, dto.CreateShipmentRequest{TrackingNumber: utils.Deref(req.TrackingNumber)})
+	case entity.OrderStatusReadyForPickup:
+		if o.DeliveryMode != entity.DeliveryModePickup {
+			return nil, errors.New("only pickup orders can be ready for pickup")
+		}
+		if err := s.repo.OrderRepo.UpdateOrderStatus(ctx, o.ID, req.Status); err != nil {
+			return nil, err
+		}
+		o.Status = req.Status
+		s.notifier.Notify(*o, orderEventReadyForPickup)
+	case entity.OrderStatusCancelled:
+		// the customer gets their money back before anything is put back, so
+		// a failed refund leaves the order as it was
+		if err := s.refundCancelledOrder(ctx, o); err != nil {
+			return nil, err
+		}
+		// cancelling puts the stock and voucher usage back
+		cancelled, err := s.repo.OrderRepo.CancelOrder(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		if !cancelled {
+			return nil, errors.New("order status changed, please retry")
+		}
+		o.Status = req.Status
+		s.notifier.Notify(*o, orderEventCancelled)
+	default:
+		if err := s.repo.OrderRepo.UpdateOrderStatus(ctx, o.ID, req.Status); err != nil {
+			return nil, err
+		}
+		o.Status = req.Status
+	}
+
+	res := toOrderResponse(*o)
+	return &res, nil
+}
+
 func (s *orderService) GetCart(ctx context.Context, customerID uint) (*dto.CartResponse, error) {
 	cart, err := s.repo.CartRepo.GetCartByCustomer(ctx, customerID)
 	if err != nil {
@@ -159,3 +249,173 @@ func (s *orderService) GetCart(ctx context.Context, customerID uint) (*dto.CartR
 	}
 	return &dto.CartResponse{CustomerID: cart.CustomerID, Items: resItems, Total: total}, nil
 }
+
+// nextOrderNumber returns a unique, sortable order number such as
+// ORD-20250131-000042, sequenced per day.
+func (s *orderService) nextOrderNumber(ctx context.Context) (string, error) {
+	day := time.Now().Format("20060102")
+	seq, err := s.repo.OrderRepo.NextOrderSequence(ctx, day)
+	if err != nil {
+		return "", err
+	}
+	prefix := s.config.OrderNumberPrefix
+	if prefix == "" {
+		prefix = "ORD"
+	}
+	return fmt.Sprintf("%s-%s-%06d", prefix, day, seq), nil
+}
+
+// snapshotOrderItem copies the product data an order needs to keep rendering
+// what was bought, even after the product is edited or removed.
+func snapshotOrderItem(v entity.ProductVariant, qty int, unitPrice float64) entity.OrderItem {
+	item := entity.OrderItem{
+		ProductVariantID: v.ID,
+		Quantity:         qty,
+		UnitPrice:        unitPrice,
+		Weight:           v.Weight,
+		ProductName:      v.Product.Name,
+		VariantName:      v.Variant,
+		SKU:              v.Product.SKU,
+	}
+	for _, p := range v.Product.Photos {
+		if p.IsDefault || item.PhotoURL == "" {
+			item.PhotoURL = p.URL
+		}
+		if p.IsDefault {
+			break
+		}
+	}
+	return item
+}
+
+func toOrderResponse(o entity.Order) dto.OrderResponse {
+	items := make([]dto.OrderItemDTO, 0, len(o.Items))
+	for _, it := range o.Items {
+		items = append(items, dto.OrderItemDTO{
+			ID:               it.ID,
+			ProductVariantID: it.ProductVariantID,
+			ProductName:      it.ProductName,
+			VariantName:      it.VariantName,
+			SKU:              it.SKU,
+			PhotoURL:         it.PhotoURL,
+			Quantity:         it.Quantity,
+			UnitPrice:        it.UnitPrice,
+		})
+	}
+	shipments := make([]dto.ShipmentDTO, 0, len(o.Shipments))
+	for _, sh := range o.Shipments {
+		shItems := make([]dto.ShipmentItemDTO, 0, len(sh.Items))
+		for _, it := range sh.Items {
+			shItems = append(shItems, dto.ShipmentItemDTO{OrderItemID: it.OrderItemID, Quantity: it.Quantity})
+		}
+		events := make([]dto.ShipmentEventDTO, 0, len(sh.Events))
+		for _, ev := range sh.Events {
+			events = append(events, dto.ShipmentEventDTO{Status: ev.Status, Description: ev.Description, Location: ev.Location, OccurredAt: ev.OccurredAt})
+		}
+		shipments = append(shipments, dto.ShipmentDTO{
+			ID:             sh.ID,
+			TrackingNumber: sh.TrackingNumber,
+			Carrier:        sh.Carrier,
+			Status:         sh.Status,
+			TrackingStatus: sh.TrackingStatus,
+			ShippedAt:      sh.ShippedAt,
+			DeliveredAt:    sh.DeliveredAt,
+			Items:          shItems,
+			Events:         events,
+		})
+	}
+	return dto.OrderResponse{
+		ID:          o.ID,
+		OrderNumber: utils.Deref(o.OrderNumber),
+		Items:       items,
+		ShippingAddress: dto.OrderAddressDTO{
+			Fullname: o.ShippingName,
+			Email:    o.ShippingEmail,
+			Phone:    o.ShippingPhone,
+			Address:  o.ShippingAddress,
+		},
+		Subtotal:         o.Subtotal,
+		Discount:         o.Discount,
+		ShippingFee:      o.ShippingFee,
+		ShippingMethod:   o.ShippingMethod,
+		TotalWeight:      o.TotalWeight,
+		DeliveryMode:     o.DeliveryMode,
+		PickupLocationID: o.PickupLocationID,
+		PickupCode:       utils.Deref(o.PickupCode),
+		PickedUpAt:       o.PickedUpAt,
+		Tax:              o.Tax,
+		GrandTotal:       o.GrandTotal,
+		Total:            o.GrandTotal,
+		WalletAmount:     o.WalletAmount,
+		GiftCardAmount:   o.GiftCardAmount,
+		AmountDue:        amountDue(o),
+		PaymentMethod:    o.PaymentMethod,
+		Status:           o.Status,
+		Shipments:        shipments,
+		PaymentExpiresAt: o.PaymentExpiresAt,
+		CreatedAt:        o.CreatedAt,
+	}
+}
+
+// Reorder copies the still-available variants of a past order into the
+// customer's cart at current prices, reporting lines that were skipped. Lines
+// are clamped to the stock not already in the cart and added all at once.
+func (s *orderService) Reorder(ctx context.Context, orderID uint, customerID uint) (*dto.ReorderResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.CustomerID != customerID {
+		return nil, errors.New("not allowed")
+	}
+
+	inCart := map[uint]int{}
+	cart, err := s.repo.CartRepo.GetCartByCustomer(ctx, customerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if cart != nil {
+		for _, it := range cart.Items {
+			inCart[it.ProductVariantID] += it.Quantity
+		}
+	}
+
+	res := &dto.ReorderResponse{Added: []dto.ReorderLine{}, Skipped: []dto.ReorderLine{}}
+	var items []entity.CartItem
+	for _, it := range o.Items {
+		line := dto.ReorderLine{ProductVariantID: it.ProductVariantID, ProductName: it.ProductName, VariantName: it.VariantName, Quantity: it.Quantity}
+		v, err := s.repo.StockRepo.GetVariantStock(ctx, it.ProductVariantID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		switch {
+		case err != nil || v.DeletedAt != nil || v.Product.DeletedAt != nil:
+			line.Reason = "deleted"
+		case !v.Product.Published:
+			line.Reason = "unpublished"
+		case v.Stock <= 0:
+			line.Reason = "out_of_stock"
+		case v.Stock <= inCart[v.ID]:
+			line.Reason = "already_in_cart"
+		}
+		if line.Reason != "" {
+			res.Skipped = append(res.Skipped, line)
+			continue
+		}
+
+		// add what is left in stock at the current price
+		if left := v.Stock - inCart[v.ID]; line.Quantity > left {
+			line.Quantity = left
+		}
+		inCart[v.ID] += line.Quantity
+		line.UnitPrice = v.Product.Price
+		items = append(items, entity.CartItem{ProductVariantID: v.ID, Quantity: line.Quantity, UnitPrice: line.UnitPrice})
+		res.Added = append(res.Added, line)
+	}
+	if len(items) > 0 {
+		if err := s.repo.CartRepo.AddItems(ctx, customerID, items); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"project-app-ecommerce-golang-tim-1/internal/data/entity"
 	"project-app-ecommerce-golang-tim-1/internal/data/repository"
 	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
 )
 
 // Minimal mock repository to test order voucher logic
@@ -29,15 +31,25 @@ func (m *mockRepo) PromotionRepo() repository.PromotionRepository { return nil }
 
 // For succinctness, we write table-driven tests by mocking PromotionRepo and CartRepo via small structs implementing the needed methods.
 
+// availableVariant returns a published variant with enough stock for checkout
+func availableVariant(id uint) entity.ProductVariant {
+	return entity.ProductVariant{Model: entity.Model{ID: id}, Stock: 10, Product: entity.Product{Name: "P1", Published: true}}
+}
+
 // Mock CartRepo
 type simpleCartRepo struct{
 	cart *entity.Cart
 }
 func (r *simpleCartRepo) GetCartByCustomer(ctx context.Context, customerID uint) (*entity.Cart, error){
-	if r.cart == nil { return nil, errors.New("no cart") }
+	if r.cart == nil { return nil, gorm.ErrRecordNotFound }
 	return r.cart, nil
 }
 func (r *simpleCartRepo) ClearCart(ctx context.Context, customerID uint) error { return nil }
+func (r *simpleCartRepo) AddItems(ctx context.Context, customerID uint, items []entity.CartItem) error {
+	if r.cart == nil { r.cart = &entity.Cart{CustomerID: customerID} }
+	r.cart.Items = append(r.cart.Items, items...)
+	return nil
+}
 
 // Mock PromotionRepo
 type simplePromoRepo struct{
@@ -48,12 +60,42 @@ func (r *simplePromoRepo) GetByVoucherCode(ctx context.Context, code string) (*e
 	return r.promo, nil
 }
 func (r *simplePromoRepo) DecrementUsage(ctx context.Context, id uint) error { return nil }
+func (r *simplePromoRepo) RestoreUsage(ctx context.Context, id uint) error { return nil }
+func (r *simplePromoRepo) ListPromotions(ctx context.Context, page, limit int, search string) ([]entity.Promotion, int64, error) { return nil, 0, nil }
+func (r *simplePromoRepo) GetPromotionByID(ctx context.Context, id uint) (*entity.Promotion, error) { return nil, errors.New("not implemented") }
+func (r *simplePromoRepo) CreatePromotion(ctx context.Context, p *entity.Promotion) error { return nil }
+func (r *simplePromoRepo) UpdatePromotion(ctx context.Context, p *entity.Promotion) error { return nil }
+func (r *simplePromoRepo) DeletePromotion(ctx context.Context, id uint) error { return nil }
+func (r *simplePromoRepo) TogglePublished(ctx context.Context, id uint, published bool) error { return nil }
+func (r *simplePromoRepo) IsVoucherCodeExists(ctx context.Context, code string, excludeID uint) (bool, error) { return false, nil }
+func (r *simplePromoRepo) ExistingProductIDs(ctx context.Context, ids []uint) ([]uint, error) { return ids, nil }
+func (r *simplePromoRepo) AttachProducts(ctx context.Context, promotionID uint, productIDs []uint) error { return nil }
+func (r *simplePromoRepo) DetachProduct(ctx context.Context, promotionID, productID uint) error { return nil }
+func (r *simplePromoRepo) ExistingCategoryIDs(ctx context.Context, ids []uint) ([]uint, error) { return ids, nil }
+func (r *simplePromoRepo) AttachCategories(ctx context.Context, promotionID uint, categoryIDs []uint) error { return nil }
+func (r *simplePromoRepo) DetachCategory(ctx context.Context, promotionID, categoryID uint) error { return nil }
 
 // Mock OrderRepo
-type simpleOrderRepo struct{}
+type simpleOrderRepo struct{ seq int; lines []repository.OrderExportRow }
 func (r *simpleOrderRepo) CreateOrder(ctx context.Context, order *entity.Order) error { order.ID = 1; return nil }
 func (r *simpleOrderRepo) GetOrderByID(ctx context.Context, id uint) (*entity.Order, error) { return nil, errors.New("not implemented") }
+func (r *simpleOrderRepo) GetOrdersByIDs(ctx context.Context, ids []uint) ([]entity.Order, error) { return nil, errors.New("not implemented") }
 func (r *simpleOrderRepo) ListOrdersByCustomer(ctx context.Context, customerID uint, limit, offset int) ([]entity.Order, int64, error) { return nil, 0, errors.New("not implemented") }
+func (r *simpleOrderRepo) GetOrderByNumber(ctx context.Context, number string) (*entity.Order, error) { return nil, errors.New("not implemented") }
+func (r *simpleOrderRepo) ListOrders(ctx context.Context, page, limit int, search, status string) ([]entity.Order, int64, error) { return nil, 0, errors.New("not implemented") }
+func (r *simpleOrderRepo) NextOrderSequence(ctx context.Context, day string) (int, error) { r.seq++; return r.seq, nil }
+func (r *simpleOrderRepo) UpdateOrderStatus(ctx context.Context, id uint, status string) error { return nil }
+func (r *simpleOrderRepo) StreamOrderLines(ctx context.Context, from, to time.Time, status string, fn func(repository.OrderExportRow) error) error {
+	for _, l := range r.lines { if err := fn(l); err != nil { return err } }
+	return nil
+}
+
+func (r *simpleOrderRepo) CountCustomerOrders(ctx context.Context, customerID uint, paymentMethod, status string) (int64, error) { return 0, nil }
+func (r *simpleOrderRepo) CountPlacedOrders(ctx context.Context, customerID uint) (int64, error) { return 0, nil }
+func (r *simpleOrderRepo) ListExpiredUnpaidOrders(ctx context.Context, now time.Time, limit int) ([]entity.Order, error) { return nil, nil }
+func (r *simpleOrderRepo) CancelOrder(ctx context.Context, o *entity.Order) (bool, error) { return true, nil }
+func (r *simpleOrderRepo) GetOrderByPickupCode(ctx context.Context, code string) (*entity.Order, error) { return nil, errors.New("not implemented") }
+func (r *simpleOrderRepo) MarkPickedUp(ctx context.Context, id uint, at time.Time) (bool, error) { return true, nil }
 
 // Combined repository for usecase.Repository expectation
 type combinedRepo struct{
@@ -76,7 +118,7 @@ func (r combinedRepo) PromotionRepo() repository.PromotionRepository { return r.
 func (r combinedRepo) GetDB() interface{} { return nil }
 
 func TestCreateOrder_WithVoucherPercentage(t *testing.T){
-	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, Quantity:2, UnitPrice:50}}}
+	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, ProductVariant: availableVariant(1), Quantity:2, UnitPrice:50}}}
 	promo := &entity.Promotion{Model: entity.Model{ID:1}, Type: "percentage", Discount: 10, StartDate: time.Now().Add(-time.Hour), EndDate: time.Now().Add(time.Hour), Published:true, UsageLimit: 5}
 
 	// build repository.Repository with our mock components
@@ -84,10 +126,11 @@ func TestCreateOrder_WithVoucherPercentage(t *testing.T){
 		CartRepo:      &simpleCartRepo{cart: cart},
 		PromotionRepo: &simplePromoRepo{promo: promo},
 		OrderRepo:     &simpleOrderRepo{},
-		AddressRepo:   nil,
+		AddressRepo:   &mockAddressRepo{},
+		ShippingRepo:  &memShippingRepo{},
 	}
 	logger, _ := zap.NewDevelopment()
-	svc := &orderService{repo: repoVal, logger: logger}
+	svc := &orderService{repo: repoVal, logger: logger, payments: testPayments()}
 	// ensure voucher code string exists
 	code := "PROMO10"
 	promo.VoucherCode = &code
@@ -99,13 +142,123 @@ func TestCreateOrder_WithVoucherPercentage(t *testing.T){
 }
 
 func TestCreateOrder_InvalidVoucher(t *testing.T){
-	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, Quantity:1, UnitPrice:100}}}
-	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{promo: nil}, OrderRepo: &simpleOrderRepo{}, AddressRepo: nil}
+	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, ProductVariant: availableVariant(1), Quantity:1, UnitPrice:100}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{promo: nil}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
 	logger, _ := zap.NewDevelopment()
-	svc := &orderService{repo: repoVal, logger: logger}
+	svc := &orderService{repo: repoVal, logger: logger, payments: testPayments()}
 	code := "INVALID"
 	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
 	_, err := svc.CreateOrder(context.Background(), req, 1)
 	if err == nil { t.Fatalf("expected error for invalid voucher") }
 }
 
+
+// Address repo returning a fully populated address for snapshot assertions
+type snapshotAddressRepo struct{ mockAddressRepo }
+
+func (r *snapshotAddressRepo) GetAddressByID(ctx context.Context, id uint) (*entity.Address, error) {
+	return &entity.Address{Model: entity.Model{ID: id}, CustomerID: 1, Fullname: "Zahra", Email: "zahra@example.com", Address: "Jl. Kebangsaan No.10"}, nil
+}
+
+func TestCreateOrder_SnapshotsProductAndAddress(t *testing.T) {
+	variant := entity.ProductVariant{
+		Model:   entity.Model{ID: 7},
+		Variant: "Red / XL",
+		Stock:   5,
+		Product: entity.Product{Name: "Hoodie", SKU: "HD-01", Published: true, Photos: []entity.ProductPhoto{{URL: "a.png"}, {URL: "b.png", IsDefault: true}}},
+	}
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 7, ProductVariant: variant, Quantity: 2, UnitPrice: 150}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &snapshotAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	logger, _ := zap.NewDevelopment()
+	svc := &orderService{repo: repoVal, logger: logger, payments: testPayments()}
+
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	it := res.Items[0]
+	if it.ProductName != "Hoodie" || it.VariantName != "Red / XL" || it.SKU != "HD-01" || it.PhotoURL != "b.png" || it.UnitPrice != 150 {
+		t.Fatalf("unexpected item snapshot: %+v", it)
+	}
+	if res.ShippingAddress.Fullname != "Zahra" || res.ShippingAddress.Address != "Jl. Kebangsaan No.10" {
+		t.Fatalf("unexpected address snapshot: %+v", res.ShippingAddress)
+	}
+}
+
+func TestQuoteOrder_ReportsErrorsWithoutWriting(t *testing.T) {
+	soldOut := availableVariant(2)
+	soldOut.Stock = 0
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{
+		{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: 100},
+		{ProductVariantID: 2, ProductVariant: soldOut, Quantity: 1, UnitPrice: 50},
+	}}
+	promo := &entity.Promotion{Model: entity.Model{ID: 4}, Type: "percentage", Discount: 10, StartDate: time.Now().Add(-time.Hour), EndDate: time.Now().Add(time.Hour), Published: true, UsageLimit: 5}
+	repoPromo := &trackingPromoRepo{promo: promo}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: repoPromo, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	logger, _ := zap.NewDevelopment()
+	svc := &orderService{repo: repoVal, logger: logger, payments: testPayments()}
+	code := "PROMO10"
+	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
+
+	res, err := svc.QuoteOrder(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Valid || len(res.Errors) != 1 {
+		t.Fatalf("expected one stock error, got %+v", res.Errors)
+	}
+	if res.Subtotal != 150 || res.Discount != 15 || res.GrandTotal != 135 {
+		t.Fatalf("unexpected quote totals: %+v", res)
+	}
+	if repoPromo.called {
+		t.Fatalf("quote must not consume voucher usage")
+	}
+	if _, err := svc.CreateOrder(context.Background(), req, 1); err == nil || err.Error() != res.Errors[0] {
+		t.Fatalf("expected CreateOrder to fail with %q, got %v", res.Errors[0], err)
+	}
+}
+
+func TestCreateOrder_AssignsSequentialOrderNumbers(t *testing.T) {
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: 100}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), config: utils.Configuration{OrderNumberPrefix: "SHOP"}, payments: testPayments()}
+	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}
+	day := time.Now().Format("20060102")
+
+	first, err := svc.CreateOrder(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.CreateOrder(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.OrderNumber != "SHOP-"+day+"-000001" || second.OrderNumber != "SHOP-"+day+"-000002" {
+		t.Fatalf("unexpected order numbers: %q, %q", first.OrderNumber, second.OrderNumber)
+	}
+}
+
+// Cart repo that records whether the cart was cleared
+type trackingCartRepo struct {
+	simpleCartRepo
+	cleared bool
+}
+
+func (r *trackingCartRepo) ClearCart(ctx context.Context, customerID uint) error { r.cleared = true; return nil }
+
+func TestCreateOrder_BuyNowLeavesCartUntouched(t *testing.T) {
+	cart := &trackingCartRepo{simpleCartRepo: simpleCartRepo{cart: &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: 100}}}}}
+	v := availableVariant(5)
+	v.Product.Price = 40
+	repoVal := repository.Repository{CartRepo: cart, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}, StockRepo: &simpleStockRepo{variants: map[uint]*entity.ProductVariant{5: &v}}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", BuyNow: &dto.BuyNowItem{ProductVariantID: 5, Quantity: 3}}
+
+	res, err := svc.CreateOrder(context.Background(), req, 1)
+	if err != nil { t.Fatalf("unexpected error: %v", err) }
+	if len(res.Items) != 1 || res.Items[0].ProductVariantID != 5 || res.GrandTotal != 120 { t.Fatalf("unexpected buy-now order: %+v", res) }
+	if cart.cleared { t.Fatalf("buy-now must not clear the cart") }
+
+	req.BuyNow.Quantity = 11
+	if _, err := svc.CreateOrder(context.Background(), req, 1); err == nil { t.Fatalf("expected stock error for buy-now quantity above stock") }
+}
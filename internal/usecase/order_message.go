@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type OrderMessageService interface {
+	ListForCustomer(ctx context.Context, orderID uint, customerID uint) (*dto.OrderMessageListResponse, error)
+	PostByCustomer(ctx context.Context, orderID uint, customerID uint, req dto.CreateOrderMessageRequest) (*dto.OrderMessageResponse, error)
+	MarkReadByCustomer(ctx context.Context, orderID uint, customerID uint) error
+	ListForAdmin(ctx context.Context, orderID uint) (*dto.OrderMessageListResponse, error)
+	PostByAdmin(ctx context.Context, orderID uint, adminID uint, role string, req dto.CreateOrderMessageRequest) (*dto.OrderMessageResponse, error)
+	MarkReadByAdmin(ctx context.Context, orderID uint) error
+}
+
+type orderMessageService struct {
+	repo     repository.Repository
+	logger   *zap.Logger
+	config   utils.Configuration
+	notifier *orderNotifier
+}
+
+func NewOrderMessageService(repo repository.Repository, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender) OrderMessageService {
+	return &orderMessageService{repo: repo, logger: logger, config: config, notifier: newOrderNotifier(emailSender, logger)}
+}
+
+// customerOrder loads the order and checks it belongs to customerID.
+func (s *orderMessageService) customerOrder(ctx context.Context, orderID uint, customerID uint) (*entity.Order, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.CustomerID != customerID {
+		return nil, errors.New("not allowed")
+	}
+	return o, nil
+}
+
+func (s *orderMessageService) ListForCustomer(ctx context.Context, orderID uint, customerID uint) (*dto.OrderMessageListResponse, error) {
+	if _, err := s.customerOrder(ctx, orderID, customerID); err != nil {
+		return nil, err
+	}
+	msgs, err := s.repo.OrderMessageRepo.ListMessages(ctx, orderID, false)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.OrderMessageListResponse{Items: make([]dto.OrderMessageResponse, 0, len(msgs))}
+	for _, m := range msgs {
+		if m.AuthorRole != "customer" && m.ReadByCustomerAt == nil {
+			res.Unread++
+		}
+		res.Items = append(res.Items, toOrderMessageResponse(m))
+	}
+	return res, nil
+}
+
+func (s *orderMessageService) PostByCustomer(ctx context.Context, orderID uint, customerID uint, req dto.CreateOrderMessageRequest) (*dto.OrderMessageResponse, error) {
+	o, err := s.customerOrder(ctx, orderID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	// customers can never post internal notes
+	m := &entity.OrderMessage{OrderID: o.ID, AuthorID: customerID, AuthorRole: "customer", Body: req.Body}
+	if err := s.repo.OrderMessageRepo.CreateMessage(ctx, m); err != nil {
+		return nil, err
+	}
+	s.notifier.NotifyMessage(s.config.SupportEmail, *o, orderEventReplyToSupport, m.Body)
+
+	res := toOrderMessageResponse(*m)
+	return &res, nil
+}
+
+func (s *orderMessageService) MarkReadByCustomer(ctx context.Context, orderID uint, customerID uint) error {
+	if _, err := s.customerOrder(ctx, orderID, customerID); err != nil {
+		return err
+	}
+	return s.repo.OrderMessageRepo.MarkReadByCustomer(ctx, orderID, time.Now())
+}
+
+func (s *orderMessageService) ListForAdmin(ctx context.Context, orderID uint) (*dto.OrderMessageListResponse, error) {
+	if _, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	msgs, err := s.repo.OrderMessageRepo.ListMessages(ctx, orderID, true)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.OrderMessageListResponse{Items: make([]dto.OrderMessageResponse, 0, len(msgs))}
+	for _, m := range msgs {
+		if m.AuthorRole == "customer" && m.ReadByAdminAt == nil {
+			res.Unread++
+		}
+		res.Items = append(res.Items, toOrderMessageResponse(m))
+	}
+	return res, nil
+}
+
+func (s *orderMessageService) PostByAdmin(ctx context.Context, orderID uint, adminID uint, role string, req dto.CreateOrderMessageRequest) (*dto.OrderMessageResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	m := &entity.OrderMessage{OrderID: o.ID, AuthorID: adminID, AuthorRole: role, Body: req.Body, Internal: req.Internal}
+	if err := s.repo.OrderMessageRepo.CreateMessage(ctx, m); err != nil {
+		return nil, err
+	}
+	if !m.Internal {
+		s.notifier.NotifyMessage(o.ShippingEmail, *o, orderEventReplyToCustomer, m.Body)
+	}
+
+	res := toOrderMessageResponse(*m)
+	return &res, nil
+}
+
+func (s *orderMessageService) MarkReadByAdmin(ctx context.Context, orderID uint) error {
+	return s.repo.OrderMessageRepo.MarkReadByAdmin(ctx, orderID, time.Now())
+}
+
+func toOrderMessageResponse(m entity.OrderMessage) dto.OrderMessageResponse {
+	return dto.OrderMessageResponse{
+		ID:               m.ID,
+		AuthorID:         m.AuthorID,
+		AuthorRole:       m.AuthorRole,
+		Body:             m.Body,
+		Internal:         m.Internal,
+		ReadByCustomerAt: m.ReadByCustomerAt,
+		ReadByAdminAt:    m.ReadByAdminAt,
+		CreatedAt:        m.CreatedAt,
+	}
+}
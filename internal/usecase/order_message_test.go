@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+)
+
+// In-memory message thread
+type memMessageRepo struct {
+	msgs []entity.OrderMessage
+}
+
+func (r *memMessageRepo) CreateMessage(ctx context.Context, m *entity.OrderMessage) error {
+	m.ID = uint(len(r.msgs) + 1)
+	r.msgs = append(r.msgs, *m)
+	return nil
+}
+
+func (r *memMessageRepo) ListMessages(ctx context.Context, orderID uint, includeInternal bool) ([]entity.OrderMessage, error) {
+	var out []entity.OrderMessage
+	for _, m := range r.msgs {
+		if m.OrderID == orderID && (includeInternal || !m.Internal) {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (r *memMessageRepo) MarkReadByCustomer(ctx context.Context, orderID uint, at time.Time) error {
+	for i := range r.msgs {
+		if r.msgs[i].OrderID == orderID && r.msgs[i].AuthorRole != "customer" && !r.msgs[i].Internal {
+			r.msgs[i].ReadByCustomerAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memMessageRepo) MarkReadByAdmin(ctx context.Context, orderID uint, at time.Time) error {
+	for i := range r.msgs {
+		if r.msgs[i].OrderID == orderID && r.msgs[i].AuthorRole == "customer" {
+			r.msgs[i].ReadByAdminAt = &at
+		}
+	}
+	return nil
+}
+
+func newMessageTestService(order *entity.Order, sender utils.EmailSender) (OrderMessageService, *memMessageRepo) {
+	msgs := &memMessageRepo{}
+	repoVal := repository.Repository{OrderRepo: &fixedOrderRepo{order: order}, OrderMessageRepo: msgs}
+	return NewOrderMessageService(repoVal, zap.NewNop(), utils.Configuration{SupportEmail: "support@example.com"}, sender), msgs
+}
+
+func TestOrderMessages_InternalNotesHiddenFromCustomer(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 5}, CustomerID: 1, ShippingEmail: "zahra@example.com"}
+	sender := &chanEmailSender{sent: make(chan sentEmail, 2)}
+	svc, _ := newMessageTestService(order, sender)
+	ctx := context.Background()
+
+	if _, err := svc.PostByAdmin(ctx, 5, 9, "admin", dto.CreateOrderMessageRequest{Body: "customer seems upset", Internal: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.PostByAdmin(ctx, 5, 9, "admin", dto.CreateOrderMessageRequest{Body: "your parcel ships tomorrow"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res, err := svc.ListForCustomer(ctx, 5, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Items) != 1 || res.Items[0].Internal || res.Unread != 1 {
+		t.Fatalf("expected only the public reply, unread, got %+v", res)
+	}
+	if m := waitEmail(t, sender); m.to != "zahra@example.com" || !strings.Contains(m.body, "your parcel ships tomorrow") {
+		t.Fatalf("unexpected email: %+v", m)
+	}
+	select {
+	case m := <-sender.sent:
+		t.Fatalf("internal note must not be emailed: %+v", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := svc.MarkReadByCustomer(ctx, 5, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, _ = svc.ListForCustomer(ctx, 5, 1)
+	if res.Unread != 0 {
+		t.Fatalf("expected thread read, got %d unread", res.Unread)
+	}
+
+	admin, _ := svc.ListForAdmin(ctx, 5)
+	if len(admin.Items) != 2 {
+		t.Fatalf("admins see internal notes, got %d messages", len(admin.Items))
+	}
+}
+
+func TestOrderMessages_CustomerCannotPostOnOthersOrder(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 5}, CustomerID: 1}
+	svc, msgs := newMessageTestService(order, nil)
+
+	if _, err := svc.PostByCustomer(context.Background(), 5, 2, dto.CreateOrderMessageRequest{Body: "hi"}); err == nil {
+		t.Fatalf("expected error for another customer's order")
+	}
+	if len(msgs.msgs) != 0 {
+		t.Fatalf("no message must be stored")
+	}
+}
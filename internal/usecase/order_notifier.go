@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"strconv"
+	"text/template"
+
+	"go.uber.org/zap"
+)
+
+type orderEvent string
+
+const (
+	orderEventPlaced    orderEvent = "placed"
+	orderEventPaid      orderEvent = "paid"
+	orderEventShipped   orderEvent = "shipped"
+	orderEventCancelled orderEvent = "cancelled"
+	// pickup order waiting at the store with its pickup code
+	orderEventReadyForPickup orderEvent = "ready_for_pickup"
+	// new message on the order thread, addressed to the customer or to support
+	orderEventReplyToCustomer orderEvent = "reply_to_customer"
+	orderEventReplyToSupport  orderEvent = "reply_to_support"
+)
+
+type orderEmailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+const orderEmailSummary = `
+{{range .Items}}- {{.ProductName}}{{if .VariantName}} ({{.VariantName}}){{end}} x{{.Quantity}} @ {{printf "%.2f" .UnitPrice}}
+{{end}}
+Subtotal: {{printf "%.2f" .Subtotal}}
+Discount: {{printf "%.2f" .Discount}}
+Shipping: {{printf "%.2f" .ShippingFee}}
+Tax: {{printf "%.2f" .Tax}}
+Total: {{printf "%.2f" .GrandTotal}}{{if .WalletAmount}}
+Paid from wallet: {{printf "%.2f" .WalletAmount}}{{end}}{{if .GiftCardAmount}}
+Paid with gift card: {{printf "%.2f" .GiftCardAmount}}{{end}}`
+
+var orderEmailTemplates = map[orderEvent]orderEmailTemplate{
+	orderEventPlaced: newOrderEmailTemplate(
+		"Order {{.Number}} received",
+		"Hi {{.ShippingName}},\n\nThanks for your order {{.Number}}. "+
+			"{{if eq .PaymentMethod \"cod\"}}Please have the amount due ready in cash when it is delivered.{{else if eq .PaymentMethod \"wallet\"}}It was paid in full from your wallet.{{else if eq .PaymentMethod \"gift_card\"}}It was paid in full with your gift card.{{else}}We will let you know once payment is confirmed.{{end}}\n"+orderEmailSummary),
+	orderEventPaid: newOrderEmailTemplate(
+		"Payment received for order {{.Number}}",
+		"Hi {{.ShippingName}},\n\nWe received your payment for order {{.Number}} and are preparing it for shipment.\n"+orderEmailSummary),
+	orderEventShipped: newOrderEmailTemplate(
+		"Order {{.Number}} has shipped",
+		"Hi {{.ShippingName}},\n\n{{if eq .Status \"partially_shipped\"}}Part of your order {{.Number}} is on its way, the rest follows in a separate shipment.{{else}}Your order {{.Number}} is on its way.{{end}}"+
+			"{{if .Tracking}}\nTracking number: {{.Tracking}}{{end}}\n"+orderEmailSummary),
+	orderEventReadyForPickup: newOrderEmailTemplate(
+		"Order {{.Number}} is ready for pickup",
+		"Hi {{.ShippingName}},\n\nYour order {{.Number}} is ready for pickup at {{.ShippingAddress}}.\n"+
+			"Show pickup code {{.PickupCode}} at the counter to collect it.\n"+orderEmailSummary),
+	orderEventCancelled: newOrderEmailTemplate(
+		"Order {{.Number}} cancelled",
+		"Hi {{.ShippingName}},\n\nYour order {{.Number}} has been cancelled.\n"+orderEmailSummary),
+	orderEventReplyToCustomer: newOrderEmailTemplate(
+		"New reply on order {{.Number}}",
+		"Hi {{.ShippingName}},\n\nOur support team replied to your order {{.Number}}:\n\n{{.Message}}\n"),
+	orderEventReplyToSupport: newOrderEmailTemplate(
+		"Customer message on order {{.Number}}",
+		"{{.ShippingName}} wrote on order {{.Number}}:\n\n{{.Message}}\n"),
+}
+
+func newOrderEmailTemplate(subject, body string) orderEmailTemplate {
+	return orderEmailTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// orderNotifier emails customers about order lifecycle events. Emails are
+// sent in the background so a failing SMTP server never fails the API call.
+type orderNotifier struct {
+	sender utils.EmailSender
+	logger *zap.Logger
+}
+
+func newOrderNotifier(sender utils.EmailSender, logger *zap.Logger) *orderNotifier {
+	return &orderNotifier{sender: sender, logger: logger}
+}
+
+// orderEmailExtra carries event specific values for the templates.
+type orderEmailExtra struct {
+	Message  string
+	Tracking string
+}
+
+// Notify emails the customer about a lifecycle event of o.
+func (n *orderNotifier) Notify(o entity.Order, ev orderEvent) {
+	n.send(o.ShippingEmail, o, ev, orderEmailExtra{})
+}
+
+// NotifyShipment emails the customer that shipment sh of o is on its way.
+func (n *orderNotifier) NotifyShipment(o entity.Order, sh entity.Shipment) {
+	n.send(o.ShippingEmail, o, orderEventShipped, orderEmailExtra{Tracking: sh.TrackingNumber})
+}
+
+// NotifyMessage emails to about a new message on the thread of o.
+func (n *orderNotifier) NotifyMessage(to string, o entity.Order, ev orderEvent, message string) {
+	n.send(to, o, ev, orderEmailExtra{Message: message})
+}
+
+func (n *orderNotifier) send(to string, o entity.Order, ev orderEvent, extra orderEmailExtra) {
+	if n == nil || n.sender == nil || to == "" {
+		return
+	}
+	subject, body, err := renderOrderEmail(o, ev, extra)
+	if err != nil {
+		n.logger.Error("failed to render order email", zap.String("event", string(ev)), zap.Error(err))
+		return
+	}
+	go func() {
+		if err := n.sender.SendEmail(to, subject, body); err != nil {
+			n.logger.Warn("failed to send order email", zap.Uint("order_id", o.ID), zap.String("event", string(ev)), zap.Error(err))
+		}
+	}()
+}
+
+func renderOrderEmail(o entity.Order, ev orderEvent, extra orderEmailExtra) (string, string, error) {
+	tpl, ok := orderEmailTemplates[ev]
+	if !ok {
+		return "", "", fmt.Errorf("no email template for order event %q", ev)
+	}
+	data := struct {
+		entity.Order
+		orderEmailExtra
+		Number string
+	}{Order: o, orderEmailExtra: extra, Number: utils.Deref(o.OrderNumber)}
+	if data.Number == "" {
+		data.Number = "#" + strconv.FormatUint(uint64(o.ID), 10)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tpl.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := tpl.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
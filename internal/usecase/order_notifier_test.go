@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+type sentEmail struct{ to, subject, body string }
+
+// Email sender that hands every message to a channel and then fails
+type chanEmailSender struct{ sent chan sentEmail }
+
+func (s *chanEmailSender) SendEmail(to, subject, body string) error {
+	s.sent <- sentEmail{to, subject, body}
+	return errors.New("smtp down")
+}
+
+func waitEmail(t *testing.T, sender *chanEmailSender) sentEmail {
+	t.Helper()
+	select {
+	case m := <-sender.sent:
+		return m
+	case <-time.After(time.Second):
+		t.Fatalf("expected an email to be sent")
+	}
+	return sentEmail{}
+}
+
+func TestUpdateOrderStatus_ShippedEmailIncludesTracking(t *testing.T) {
+	number := "ORD-20250101-000001"
+	order := &entity.Order{Model: entity.Model{ID: 3}, OrderNumber: &number, CustomerID: 1, ShippingName: "Zahra", ShippingEmail: "zahra@example.com", Status: entity.OrderStatusProcessing, GrandTotal: 120,
+		Items: []entity.OrderItem{{ProductName: "Hoodie", VariantName: "XL", Quantity: 1, UnitPrice: 120}}}
+	sender := &chanEmailSender{sent: make(chan sentEmail, 1)}
+	repoVal := repository.Repository{OrderRepo: &copyingOrderRepo{order: order}, ShipmentRepo: &memShipmentRepo{order: order}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), notifier: newOrderNotifier(sender, zap.NewNop())}
+
+	tracking := "JNE123"
+	if _, err := svc.UpdateOrderStatus(context.Background(), 3, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusShipped, TrackingNumber: &tracking}); err != nil {
+		t.Fatalf("a failing smtp server must not fail the call: %v", err)
+	}
+	m := waitEmail(t, sender)
+	if m.to != "zahra@example.com" || !strings.Contains(m.subject, number) {
+		t.Fatalf("unexpected email: %+v", m)
+	}
+	for _, want := range []string{"JNE123", "Hoodie (XL) x1", "Total: 120.00"} {
+		if !strings.Contains(m.body, want) {
+			t.Fatalf("email body missing %q:\n%s", want, m.body)
+		}
+	}
+}
+
+func TestUpdateOrderStatus_RejectsInvalidTransition(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 3}, Status: entity.OrderStatusCreated}
+	svc := &orderService{repo: repository.Repository{OrderRepo: &fixedOrderRepo{order: order}}, logger: zap.NewNop()}
+	if _, err := svc.UpdateOrderStatus(context.Background(), 3, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusDelivered}); err == nil {
+		t.Fatalf("expected created -> delivered to be rejected")
+	}
+}
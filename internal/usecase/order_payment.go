@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PayOrder starts the payment of a created order at the provider of its
+// payment method. A charge that is still pending is reused, so paying twice
+// never charges twice.
+func (s *orderService) PayOrder(ctx context.Context, orderID uint, customerID uint) (*dto.PaymentResponse, error) {
+	o, err := s.customerOrder(ctx, orderID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if o.PaymentMethod == payment.MethodCOD {
+		return nil, errors.New("cash on delivery orders are paid to the courier")
+	}
+	if o.Status != entity.OrderStatusCreated {
+		return nil, fmt.Errorf("order is already %s", o.Status)
+	}
+	if o.PaymentExpiresAt != nil && o.PaymentExpiresAt.Before(time.Now()) {
+		return nil, errors.New("payment deadline has passed")
+	}
+	provider, err := s.payments.Get(o.PaymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	if p, err := s.repo.PaymentRepo.GetLatestPaymentByOrder(ctx, o.ID); err == nil && p.Status == string(payment.StatusPending) {
+		// unless the provider lost it, e.g. the mock gateway after a restart
+		if _, err := provider.GetCharge(ctx, p.ChargeID); !errors.Is(err, payment.ErrChargeNotFound) {
+			res := toPaymentResponse(*p)
+			return &res, nil
+		}
+	}
+
+	ch, err := provider.CreateCharge(ctx, payment.ChargeRequest{Reference: orderLabel(*o), Amount: amountDue(*o), Email: o.ShippingEmail})
+	if err != nil {
+		return nil, err
+	}
+	p := &entity.Payment{
+		OrderID:    o.ID,
+		Method:     o.PaymentMethod,
+		ChargeID:   ch.ID,
+		Amount:     ch.Amount,
+		Status:     string(ch.Status),
+		PaymentURL: ch.PaymentURL,
+	}
+	if err := s.repo.PaymentRepo.CreatePayment(ctx, p); err != nil {
+		return nil, err
+	}
+	res := toPaymentResponse(*p)
+	return &res, nil
+}
+
+// GetPayment returns the latest payment of the order, refreshed from its provider.
+func (s *orderService) GetPayment(ctx context.Context, orderID uint, customerID uint) (*dto.PaymentResponse, error) {
+	o, err := s.customerOrder(ctx, orderID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.repo.PaymentRepo.GetLatestPaymentByOrder(ctx, o.ID)
+	if err != nil {
+		return nil, errors.New("order has no payment yet")
+	}
+	provider, err := s.payments.Get(p.Method)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := provider.GetCharge(ctx, p.ChargeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyCharge(ctx, o, p, ch); err != nil {
+		return nil, err
+	}
+	res := toPaymentResponse(*p)
+	return &res, nil
+}
+
+// RefundPayment refunds the paid charge of an order, in full when no amount is given.
+func (s *orderService) RefundPayment(ctx context.Context, orderID uint, req dto.RefundPaymentRequest) (*dto.PaymentResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.repo.PaymentRepo.GetLatestPaymentByOrder(ctx, o.ID)
+	if err != nil {
+		return nil, errors.New("order has no payment yet")
+	}
+	if p.Status != string(payment.StatusPaid) && p.Status != string(payment.StatusPartiallyRefunded) {
+		return nil, fmt.Errorf("cannot refund a %s payment", p.Status)
+	}
+	amount := roundMoney(p.Amount - p.RefundedAmount)
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	if req.ToWallet {
+		return s.refundToWallet(ctx, o, p, amount)
+	}
+	provider, err := s.payments.Get(p.Method)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := provider.Refund(ctx, p.ChargeID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyCharge(ctx, o, p, ch); err != nil {
+		return nil, err
+	}
+	res := toPaymentResponse(*p)
+	return &res, nil
+}
+
+// refundCancelledOrder refunds what is left of the order's paid charge at its
+// provider. Collected cash has no provider to go back through, so it is
+// refunded to the wallet. Orders that were never charged, e.g. paid in full
+// from the wallet, have nothing to refund here.
+func (s *orderService) refundCancelledOrder(ctx context.Context, o *entity.Order) error {
+	p, err := s.repo.PaymentRepo.GetLatestPaymentByOrder(ctx, o.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if p.Status != string(payment.StatusPaid) && p.Status != string(payment.StatusPartiallyRefunded) {
+		return nil
+	}
+	if p.Method == payment.MethodCOD {
+		_, err := s.refundToWallet(ctx, o, p, p.Amount-p.RefundedAmount)
+		return err
+	}
+	provider, err := s.payments.Get(p.Method)
+	if err != nil {
+		return err
+	}
+	ch, err := provider.Refund(ctx, p.ChargeID, roundMoney(p.Amount-p.RefundedAmount))
+	if err != nil {
+		return fmt.Errorf("failed to refund the payment, order not cancelled: %w", err)
+	}
+	return s.applyCharge(ctx, o, p, ch)
+}
+
+// refundToWallet refunds amount of p as store credit, without involving the
+// payment provider.
+func (s *orderService) refundToWallet(ctx context.Context, o *entity.Order, p *entity.Payment, amount float64) (*dto.PaymentResponse, error) {
+	amount = roundMoney(amount)
+	left := roundMoney(p.Amount - p.RefundedAmount)
+	if amount > left {
+		return nil, fmt.Errorf("cannot refund %.2f, only %.2f is left", amount, left)
+	}
+	p.RefundedAmount = roundMoney(p.RefundedAmount + amount)
+	p.Status = string(payment.StatusPartiallyRefunded)
+	if p.RefundedAmount == roundMoney(p.Amount) {
+		p.Status = string(payment.StatusRefunded)
+	}
+	e := &entity.WalletEntry{
+		CustomerID: o.CustomerID,
+		Amount:     amount,
+		Reason:     "refund",
+		OrderID:    &o.ID,
+		PaymentID:  &p.ID,
+	}
+	if err := s.repo.PaymentRepo.RefundToWallet(ctx, p, e); err != nil {
+		return nil, err
+	}
+	res := toPaymentResponse(*p)
+	return &res, nil
+}
+
+// applyCharge records the charge state reported by the provider on p. Only
+// a paid charge covering what the wallet did not moves a created order to paid.
+func (s *orderService) applyCharge(ctx context.Context, o *entity.Order, p *entity.Payment, ch *payment.Charge) error {
+	if ch.ID != p.ChargeID {
+		return errors.New("charge does not belong to this payment")
+	}
+	if string(ch.Status) == p.Status && ch.RefundedAmount == p.RefundedAmount {
+		return nil
+	}
+	p.Status = string(ch.Status)
+	p.RefundedAmount = ch.RefundedAmount
+
+	orderStatus := ""
+	if ch.Status == payment.StatusPaid {
+		if p.PaidAt == nil {
+			now := time.Now()
+			p.PaidAt = &now
+		}
+		switch {
+		case o.Status == entity.OrderStatusCancelled:
+			// e.g. paid right after the payment deadline; needs a refund
+			s.logger.Warn("payment received for cancelled order", zap.Uint("order_id", o.ID), zap.String("charge_id", ch.ID))
+			p.NeedsRefund = true
+		case o.Status != entity.OrderStatusCreated:
+			// already paid before
+		case roundMoney(ch.Amount) >= amountDue(*o):
+			orderStatus = entity.OrderStatusPaid
+		default:
+			s.logger.Warn("paid amount does not cover amount due", zap.Uint("order_id", o.ID), zap.String("charge_id", ch.ID),
+				zap.Float64("amount", ch.Amount), zap.Float64("amount_due", amountDue(*o)))
+		}
+	}
+	moved, err := s.repo.PaymentRepo.UpdatePayment(ctx, p, orderStatus)
+	if err != nil {
+		return err
+	}
+	if moved {
+		o.Status = orderStatus
+		s.notifier.Notify(*o, orderEventPaid)
+	}
+	return nil
+}
+
+// customerOrder loads the order and checks it belongs to customerID.
+func (s *orderService) customerOrder(ctx context.Context, orderID uint, customerID uint) (*entity.Order, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.CustomerID != customerID {
+		return nil, errors.New("not allowed")
+	}
+	return o, nil
+}
+
+func toPaymentResponse(p entity.Payment) dto.PaymentResponse {
+	return dto.PaymentResponse{
+		OrderID:        p.OrderID,
+		Method:         p.Method,
+		ChargeID:       p.ChargeID,
+		Amount:         p.Amount,
+		RefundedAmount: p.RefundedAmount,
+		Status:         p.Status,
+		PaymentURL:     p.PaymentURL,
+		PaidAt:         p.PaidAt,
+		CollectedBy:    p.CollectedBy,
+		NeedsRefund:    p.NeedsRefund,
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+)
+
+// testPayments accepts "gopay", collected by the mock gateway
+func testPayments() *payment.Registry {
+	payments := payment.NewRegistry([]string{"gopay"})
+	payments.Register("gopay", payment.NewMockProvider("/payments/mock/charges", "test-secret"))
+	return payments
+}
+
+// In-memory payment store that also applies order status changes
+type memPaymentRepo struct {
+	order    *entity.Order
+	payments []entity.Payment
+	events   []entity.PaymentEvent
+	logs     []entity.PaymentWebhookLog
+	wallet   []entity.WalletEntry
+}
+
+func (r *memPaymentRepo) CreatePayment(ctx context.Context, p *entity.Payment) error {
+	p.ID = uint(len(r.payments) + 1)
+	r.payments = append(r.payments, *p)
+	return nil
+}
+
+func (r *memPaymentRepo) GetPaymentByChargeID(ctx context.Context, chargeID string) (*entity.Payment, error) {
+	for _, p := range r.payments {
+		if p.ChargeID == chargeID {
+			return &p, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memPaymentRepo) GetLatestPaymentByOrder(ctx context.Context, orderID uint) (*entity.Payment, error) {
+	for i := len(r.payments) - 1; i >= 0; i-- {
+		if r.payments[i].OrderID == orderID {
+			p := r.payments[i]
+			return &p, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memPaymentRepo) UpdatePayment(ctx context.Context, p *entity.Payment, orderStatus string) (bool, error) {
+	moved := orderStatus != "" && r.order.Status == entity.OrderStatusCreated
+	if moved {
+		r.order.Status = orderStatus
+	} else if orderStatus != "" {
+		p.NeedsRefund = true
+	}
+	r.payments[p.ID-1] = *p
+	return moved, nil
+}
+
+func (r *memPaymentRepo) RefundToWallet(ctx context.Context, p *entity.Payment, e *entity.WalletEntry) error {
+	r.payments[p.ID-1] = *p
+	e.Type = entity.WalletEntryCredit
+	r.wallet = append(r.wallet, *e)
+	return nil
+}
+
+func (r *memPaymentRepo) RecordEvent(ctx context.Context, ev *entity.PaymentEvent) (bool, error) {
+	for _, e := range r.events {
+		if e.Method == ev.Method && e.EventID == ev.EventID {
+			return false, nil
+		}
+	}
+	ev.ID = uint(len(r.events) + 1)
+	r.events = append(r.events, *ev)
+	return true, nil
+}
+
+func (r *memPaymentRepo) DeleteEvent(ctx context.Context, id uint) error {
+	for i, e := range r.events {
+		if e.ID == id {
+			r.events = append(r.events[:i], r.events[i+1:]...)
+		}
+	}
+	return nil
+}
+
+func (r *memPaymentRepo) ListSettledPayments(ctx context.Context, method string, from, to time.Time) ([]entity.Payment, error) {
+	var res []entity.Payment
+	for _, p := range r.payments {
+		if p.Method == method && p.PaidAt != nil && !p.PaidAt.Before(from) && p.PaidAt.Before(to) {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+func (r *memPaymentRepo) CreateWebhookLog(ctx context.Context, l *entity.PaymentWebhookLog) error {
+	r.logs = append(r.logs, *l)
+	return nil
+}
+
+func paymentTestService(order *entity.Order) (*orderService, *payment.MockProvider, *memPaymentRepo) {
+	mock := payment.NewMockProvider("/payments/mock/charges", "test-secret")
+	payments := payment.NewRegistry([]string{"gopay"})
+	payments.Register("gopay", mock)
+	paymentRepo := &memPaymentRepo{order: order}
+	repoVal := repository.Repository{OrderRepo: &copyingOrderRepo{order: order}, PaymentRepo: paymentRepo}
+	return &orderService{repo: repoVal, logger: zap.NewNop(), payments: payments}, mock, paymentRepo
+}
+
+func TestPayOrder_PaidOnlyAfterVerifiedCharge(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 150, Status: entity.OrderStatusCreated}
+	svc, mock, _ := paymentTestService(order)
+	ctx := context.Background()
+
+	started, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again, _ := svc.PayOrder(ctx, 1, 1); again.ChargeID != started.ChargeID {
+		t.Fatalf("a pending charge must be reused, got %s and %s", started.ChargeID, again.ChargeID)
+	}
+
+	res, err := svc.GetPayment(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != string(payment.StatusPending) || order.Status != entity.OrderStatusCreated {
+		t.Fatalf("order must stay created while the charge is pending, got %s / %s", res.Status, order.Status)
+	}
+
+	if _, err := mock.Complete(started.ChargeID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, _ = svc.GetPayment(ctx, 1, 1)
+	if res.Status != string(payment.StatusPaid) || res.PaidAt == nil || order.Status != entity.OrderStatusPaid {
+		t.Fatalf("expected paid order, got %s / %s", res.Status, order.Status)
+	}
+
+	res, err = svc.RefundPayment(ctx, 1, dto.RefundPaymentRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != string(payment.StatusRefunded) || res.RefundedAmount != 150 {
+		t.Fatalf("expected full refund, got %+v", res)
+	}
+}
+
+func TestPayOrder_NewChargeWhenGatewayForgotPendingOne(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 150, Status: entity.OrderStatusCreated}
+	svc, _, _ := paymentTestService(order)
+	ctx := context.Background()
+
+	started, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the mock gateway restarts and forgets its charges
+	svc.payments.Register("gopay", payment.NewMockProvider("/payments/mock/charges", "test-secret"))
+	again, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.ChargeID == started.ChargeID {
+		t.Fatalf("expected a new charge with a fresh id, got %s twice", again.ChargeID)
+	}
+}
+
+func TestApplyCharge_OrderCancelledMeanwhileNeedsRefund(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 150, Status: entity.OrderStatusCreated}
+	svc, mock, payments := paymentTestService(order)
+	ctx := context.Background()
+
+	started, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ch, _ := mock.Complete(started.ChargeID)
+	stale := *order
+	order.Status = entity.OrderStatusCancelled // e.g. by the expiry worker
+	p, _ := payments.GetPaymentByChargeID(ctx, ch.ID)
+	if err := svc.applyCharge(ctx, &stale, p, ch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != entity.OrderStatusCancelled || stale.Status != entity.OrderStatusCreated {
+		t.Fatalf("a cancelled order must not become paid, got %s", order.Status)
+	}
+	if res, _ := svc.GetPayment(ctx, 1, 1); res.Status != string(payment.StatusPaid) || !res.NeedsRefund {
+		t.Fatalf("expected the payment to be flagged for refund, got %+v", res)
+	}
+}
+
+func TestUpdateOrderStatus_CancellingPaidOrderRefunds(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 150, Status: entity.OrderStatusCreated}
+	svc, mock, payments := paymentTestService(order)
+	ctx := context.Background()
+
+	started, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mock.Complete(started.ChargeID)
+	if res, _ := svc.GetPayment(ctx, 1, 1); res.Status != string(payment.StatusPaid) {
+		t.Fatalf("expected a paid order, got %+v", res)
+	}
+
+	res, err := svc.UpdateOrderStatus(ctx, 1, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusCancelled})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusCancelled {
+		t.Fatalf("expected a cancelled order, got %s", res.Status)
+	}
+	p, _ := payments.GetPaymentByChargeID(ctx, started.ChargeID)
+	if ch, _ := mock.GetCharge(ctx, started.ChargeID); p.Status != string(payment.StatusRefunded) || p.RefundedAmount != 150 || ch.RefundedAmount != 150 {
+		t.Fatalf("expected the charge to be refunded in full, got %+v", p)
+	}
+}
+
+func TestUpdateOrderStatus_CannotMarkPaidManually(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, Status: entity.OrderStatusCreated}
+	svc, _, _ := paymentTestService(order)
+	if _, err := svc.UpdateOrderStatus(context.Background(), 1, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusPaid}); err == nil {
+		t.Fatalf("orders must only become paid through a verified payment")
+	}
+}
+
+func TestCreateOrder_RejectsPaymentMethodNotAllowed(t *testing.T) {
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: 100}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+
+	_, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "bitcoin"}, 1)
+	if err == nil || err.Error() != "payment method bitcoin is not supported" {
+		t.Fatalf("expected unsupported payment method, got %v", err)
+	}
+}
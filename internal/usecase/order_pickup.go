@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"strings"
+	"time"
+)
+
+// pickupCodeLength is the length of the code customers show when collecting.
+const pickupCodeLength = 8
+
+// applyPickup makes c a click-and-collect order at locationID. Pickup orders
+// ship nothing, so they have no shipping method and no shipping fee; the
+// customer's address only supplies the contact details.
+func (s *orderService) applyPickup(ctx context.Context, c *checkout, locationID *uint) {
+	c.order.DeliveryMode = entity.DeliveryModePickup
+	if locationID == nil {
+		c.reject("choose a pickup location")
+		return
+	}
+	l, err := s.repo.PickupRepo.GetLocation(ctx, *locationID)
+	if err != nil || !l.Active {
+		c.reject("pickup location is not available")
+		return
+	}
+	c.order.PickupLocationID = &l.ID
+	c.order.ShippingMethod = "Pickup at " + l.Name
+	c.order.ShippingAddress = l.Name + ", " + l.Address
+}
+
+// VerifyPickup hands over the order whose pickup code staff scanned. The
+// order must be ready for pickup, and cash on delivery orders paid first.
+func (s *orderService) VerifyPickup(ctx context.Context, req dto.VerifyPickupRequest) (*dto.OrderResponse, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	o, err := s.repo.OrderRepo.GetOrderByPickupCode(ctx, code)
+	if err != nil {
+		return nil, errors.New("pickup code not found")
+	}
+	switch o.Status {
+	case entity.OrderStatusReadyForPickup:
+	case entity.OrderStatusPickedUp:
+		return nil, fmt.Errorf("order %s was already picked up", orderLabel(*o))
+	default:
+		return nil, fmt.Errorf("order %s is %s, not ready for pickup", orderLabel(*o), o.Status)
+	}
+	if o.PaymentMethod == payment.MethodCOD {
+		if p, err := s.repo.PaymentRepo.GetLatestPaymentByOrder(ctx, o.ID); err != nil || p.Status != string(payment.StatusPaid) {
+			return nil, errors.New("collect the payment before handing over the order")
+		}
+	}
+
+	now := time.Now()
+	ok, err := s.repo.OrderRepo.MarkPickedUp(ctx, o.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("order status changed, please retry")
+	}
+	o.Status = entity.OrderStatusPickedUp
+	o.PickedUpAt = &now
+
+	res := toOrderResponse(*o)
+	return &res, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+// Pickup locations: 1 is open, 2 is closed
+type memPickupRepo struct{}
+
+func (r *memPickupRepo) ListLocations(ctx context.Context, all bool) ([]entity.PickupLocation, error) {
+	return nil, nil
+}
+
+func (r *memPickupRepo) GetLocation(ctx context.Context, id uint) (*entity.PickupLocation, error) {
+	switch id {
+	case 1:
+		return &entity.PickupLocation{Model: entity.Model{ID: 1}, Name: "Braga Store", Address: "Jl. Braga No.5, Bandung", Active: true}, nil
+	case 2:
+		return &entity.PickupLocation{Model: entity.Model{ID: 2}, Name: "Dago Store", Address: "Jl. Dago No.1, Bandung"}, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memPickupRepo) SaveLocation(ctx context.Context, l *entity.PickupLocation) error { return nil }
+func (r *memPickupRepo) DeleteLocation(ctx context.Context, id uint) error                { return nil }
+
+// Order repo finding the stored order by its pickup code
+type pickupOrderRepo struct {
+	copyingOrderRepo
+}
+
+func (r *pickupOrderRepo) GetOrderByPickupCode(ctx context.Context, code string) (*entity.Order, error) {
+	if r.order.PickupCode == nil || *r.order.PickupCode != code {
+		return nil, errors.New("record not found")
+	}
+	return r.GetOrderByID(ctx, r.order.ID)
+}
+
+func (r *pickupOrderRepo) UpdateOrderStatus(ctx context.Context, id uint, status string) error {
+	r.order.Status = status
+	return nil
+}
+
+func (r *pickupOrderRepo) MarkPickedUp(ctx context.Context, id uint, at time.Time) (bool, error) {
+	if r.order.Status != entity.OrderStatusReadyForPickup {
+		return false, nil
+	}
+	r.order.Status = entity.OrderStatusPickedUp
+	r.order.PickedUpAt = &at
+	return true, nil
+}
+
+func TestCheckout_PickupSkipsShipping(t *testing.T) {
+	svc := shippingCheckoutService(800, 2, 100, entity.Address{CustomerID: 1, Fullname: "Zahra", Province: "DKI Jakarta", PostalCode: "10110"})
+	svc.repo.PickupRepo = &memPickupRepo{}
+
+	store := uint(1)
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", DeliveryMode: entity.DeliveryModePickup, PickupLocationID: &store}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.ShippingFee != 0 || res.GrandTotal != 200 || res.DeliveryMode != entity.DeliveryModePickup || *res.PickupLocationID != 1 {
+		t.Fatalf("expected a pickup order without shipping fee, got %+v", res)
+	}
+	if len(res.PickupCode) != pickupCodeLength || res.ShippingAddress.Address != "Braga Store, Jl. Braga No.5, Bandung" || res.ShippingAddress.Fullname != "Zahra" {
+		t.Fatalf("expected a pickup code and the store as address, got %+v", res)
+	}
+
+	closed := uint(2)
+	q, _ := svc.QuoteOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", DeliveryMode: entity.DeliveryModePickup, PickupLocationID: &closed}, 1)
+	if q.Valid || q.Errors[0] != "pickup location is not available" {
+		t.Fatalf("expected an inactive location to be rejected, got %+v", q)
+	}
+	q, _ = svc.QuoteOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", DeliveryMode: entity.DeliveryModePickup}, 1)
+	if q.Valid || q.Errors[0] != "choose a pickup location" {
+		t.Fatalf("expected pickup without a location to be rejected, got %+v", q)
+	}
+}
+
+func TestPickup_ReadyThenVerified(t *testing.T) {
+	code := "K7PX2M9Q"
+	order := &entity.Order{Model: entity.Model{ID: 1}, Status: entity.OrderStatusProcessing, DeliveryMode: entity.DeliveryModePickup, PickupCode: &code, PaymentMethod: "gopay",
+		ShippingName: "Zahra", ShippingEmail: "zahra@example.com", ShippingAddress: "Braga Store, Jl. Braga No.5, Bandung"}
+	sender := &chanEmailSender{sent: make(chan sentEmail, 1)}
+	svc := shipmentTestService(order)
+	svc.repo.OrderRepo = &pickupOrderRepo{copyingOrderRepo{order: order}}
+	svc.notifier = newOrderNotifier(sender, zap.NewNop())
+	ctx := context.Background()
+
+	if _, err := svc.VerifyPickup(ctx, dto.VerifyPickupRequest{Code: code}); err == nil {
+		t.Fatalf("expected an order that is not ready to be refused")
+	}
+	if _, err := svc.UpdateOrderStatus(ctx, 1, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusShipped, TrackingNumber: &code}); err == nil {
+		t.Fatalf("expected pickup orders not to ship")
+	}
+	if _, err := svc.UpdateOrderStatus(ctx, 1, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusReadyForPickup}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m := waitEmail(t, sender); !strings.Contains(m.body, "Braga Store") || !strings.Contains(m.body, "pickup code "+code) {
+		t.Fatalf("expected the email to name the store and pickup code:\n%s", m.body)
+	}
+	if _, err := svc.VerifyPickup(ctx, dto.VerifyPickupRequest{Code: "WRONG123"}); err == nil {
+		t.Fatalf("expected an unknown code to be refused")
+	}
+
+	res, err := svc.VerifyPickup(ctx, dto.VerifyPickupRequest{Code: " k7px2m9q "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusPickedUp || res.PickedUpAt == nil || order.Status != entity.OrderStatusPickedUp {
+		t.Fatalf("expected the order to be picked up, got %+v", res)
+	}
+	if _, err := svc.VerifyPickup(ctx, dto.VerifyPickupRequest{Code: code}); err == nil {
+		t.Fatalf("expected a second scan to be refused")
+	}
+}
+
+func TestUpdateOrderStatus_ReadyForPickupNeedsPickupOrder(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, Status: entity.OrderStatusProcessing, DeliveryMode: entity.DeliveryModeShipping}
+	svc := shipmentTestService(order)
+	if _, err := svc.UpdateOrderStatus(context.Background(), 1, dto.UpdateOrderStatusRequest{Status: entity.OrderStatusReadyForPickup}); err == nil {
+		t.Fatalf("expected a shipping order not to become ready for pickup")
+	}
+}
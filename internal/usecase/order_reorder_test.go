@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+)
+
+// Order repo serving a single stored order
+type fixedOrderRepo struct {
+	simpleOrderRepo
+	order *entity.Order
+}
+
+func (r *fixedOrderRepo) GetOrderByID(ctx context.Context, id uint) (*entity.Order, error) {
+	if r.order == nil || r.order.ID != id {
+		return nil, errors.New("record not found")
+	}
+	return r.order, nil
+}
+
+// Stock repo backed by an in-memory variant map
+type simpleStockRepo struct {
+	repository.StockRepository
+	variants map[uint]*entity.ProductVariant
+}
+
+func (r *simpleStockRepo) GetVariantStock(ctx context.Context, variantID uint) (*entity.ProductVariant, error) {
+	v, ok := r.variants[variantID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return v, nil
+}
+
+func TestReorder_SkipsUnavailableLines(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 9}, CustomerID: 1, Items: []entity.OrderItem{
+		{ProductVariantID: 1, Quantity: 3, UnitPrice: 80},
+		{ProductVariantID: 2, Quantity: 1, UnitPrice: 50},
+		{ProductVariantID: 3, Quantity: 1, UnitPrice: 50},
+		{ProductVariantID: 4, Quantity: 1, UnitPrice: 50},
+	}}
+	inStock := availableVariant(1)
+	inStock.Stock = 2
+	inStock.Product.Price = 95
+	unpublished := availableVariant(2)
+	unpublished.Product.Published = false
+	soldOut := availableVariant(3)
+	soldOut.Stock = 0
+	stock := &simpleStockRepo{variants: map[uint]*entity.ProductVariant{1: &inStock, 2: &unpublished, 3: &soldOut}}
+	cart := &simpleCartRepo{}
+	repoVal := repository.Repository{CartRepo: cart, OrderRepo: &fixedOrderRepo{order: order}, StockRepo: stock}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop()}
+
+	res, err := svc.Reorder(context.Background(), 9, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Added) != 1 || res.Added[0].Quantity != 2 || res.Added[0].UnitPrice != 95 {
+		t.Fatalf("expected one line clamped to stock at current price, got %+v", res.Added)
+	}
+	reasons := map[uint]string{}
+	for _, l := range res.Skipped {
+		reasons[l.ProductVariantID] = l.Reason
+	}
+	if reasons[2] != "unpublished" || reasons[3] != "out_of_stock" || reasons[4] != "deleted" {
+		t.Fatalf("unexpected skip reasons: %v", reasons)
+	}
+	if len(cart.cart.Items) != 1 {
+		t.Fatalf("expected one cart line, got %d", len(cart.cart.Items))
+	}
+
+	if _, err := svc.Reorder(context.Background(), 9, 2); err == nil {
+		t.Fatalf("expected error reordering another customer's order")
+	}
+}
+
+// Stock repo whose database goes down when variant failAt is looked up
+type failingStockRepo struct {
+	simpleStockRepo
+	failAt uint
+}
+
+func (r *failingStockRepo) GetVariantStock(ctx context.Context, variantID uint) (*entity.ProductVariant, error) {
+	if variantID == r.failAt {
+		return nil, errors.New("connection refused")
+	}
+	return r.simpleStockRepo.GetVariantStock(ctx, variantID)
+}
+
+func TestReorder_ClampsToStockLeftAfterCart(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 9}, CustomerID: 1, Items: []entity.OrderItem{
+		{ProductVariantID: 1, Quantity: 3, UnitPrice: 80},
+		{ProductVariantID: 2, Quantity: 2, UnitPrice: 50},
+	}}
+	shirt := availableVariant(1)
+	shirt.Stock = 5
+	mug := availableVariant(2)
+	mug.Stock = 2
+	stock := &simpleStockRepo{variants: map[uint]*entity.ProductVariant{1: &shirt, 2: &mug}}
+	cart := &simpleCartRepo{cart: &entity.Cart{CustomerID: 1, Items: []entity.CartItem{
+		{ProductVariantID: 1, Quantity: 4, UnitPrice: 80},
+		{ProductVariantID: 2, Quantity: 2, UnitPrice: 50},
+	}}}
+	repoVal := repository.Repository{CartRepo: cart, OrderRepo: &fixedOrderRepo{order: order}, StockRepo: stock}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop()}
+
+	res, err := svc.Reorder(context.Background(), 9, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Added) != 1 || res.Added[0].Quantity != 1 {
+		t.Fatalf("expected the shirt clamped to the one left after the cart, got %+v", res.Added)
+	}
+	if len(res.Skipped) != 1 || res.Skipped[0].Reason != "already_in_cart" {
+		t.Fatalf("expected the mug to be skipped as already in the cart, got %+v", res.Skipped)
+	}
+
+	cart.cart.Items = nil
+	svc.repo.StockRepo = &failingStockRepo{simpleStockRepo: *stock, failAt: 2}
+	if _, err := svc.Reorder(context.Background(), 9, 1); err == nil || err.Error() != "connection refused" {
+		t.Fatalf("expected a database error to be passed up, got %v", err)
+	}
+	if len(cart.cart.Items) != 0 {
+		t.Fatalf("expected nothing to be added when a line fails, got %+v", cart.cart.Items)
+	}
+}
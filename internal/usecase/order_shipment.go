@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"time"
+)
+
+// CreateShipment ships a subset of an order's items under one tracking
+// number. Without items, everything not shipped yet goes in the shipment.
+func (s *orderService) CreateShipment(ctx context.Context, orderID uint, req dto.CreateShipmentRequest) (*dto.OrderResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != entity.OrderStatusProcessing && o.Status != entity.OrderStatusPartiallyShipped {
+		return nil, fmt.Errorf("cannot ship an order that is %s", o.Status)
+	}
+	if o.DeliveryMode == entity.DeliveryModePickup {
+		return nil, errors.New("pickup orders are collected in store, not shipped")
+	}
+	if req.TrackingNumber == "" && req.Carrier == "" {
+		return nil, errors.New("tracking_number is required to ship an order")
+	}
+
+	remaining := remainingToShip(*o)
+	sh := entity.Shipment{
+		OrderID:        o.ID,
+		TrackingNumber: req.TrackingNumber,
+		Carrier:        req.Carrier,
+		Status:         entity.ShipmentStatusShipped,
+		ShippedAt:      time.Now(),
+	}
+	if len(req.Items) == 0 {
+		for _, it := range o.Items {
+			if remaining[it.ID] > 0 {
+				sh.Items = append(sh.Items, entity.ShipmentItem{OrderItemID: it.ID, Quantity: remaining[it.ID]})
+			}
+		}
+	}
+	for _, it := range req.Items {
+		left, ok := remaining[it.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("order item %d does not belong to this order", it.OrderItemID)
+		}
+		if it.Quantity <= 0 || it.Quantity > left {
+			return nil, fmt.Errorf("only %d of order item %d left to ship", left, it.OrderItemID)
+		}
+		remaining[it.OrderItemID] -= it.Quantity
+		sh.Items = append(sh.Items, entity.ShipmentItem{OrderItemID: it.OrderItemID, Quantity: it.Quantity})
+	}
+	if len(sh.Items) == 0 {
+		return nil, errors.New("nothing left to ship")
+	}
+	if sh.TrackingNumber == "" {
+		if sh.TrackingNumber, err = s.bookShipment(ctx, *o, sh); err != nil {
+			return nil, err
+		}
+	}
+
+	o.Shipments = append(o.Shipments, sh)
+	status := deriveOrderStatus(*o)
+	if err := s.repo.ShipmentRepo.CreateShipment(ctx, &sh, status); err != nil {
+		return nil, err
+	}
+	o.Shipments[len(o.Shipments)-1] = sh
+	o.Status = status
+
+	s.notifier.NotifyShipment(*o, sh)
+
+	res := toOrderResponse(*o)
+	return &res, nil
+}
+
+// UpdateShipmentStatus marks a shipment delivered and re-derives the order status.
+func (s *orderService) UpdateShipmentStatus(ctx context.Context, orderID, shipmentID uint, req dto.UpdateShipmentStatusRequest) (*dto.OrderResponse, error) {
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, sh := range o.Shipments {
+		if sh.ID == shipmentID {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, errors.New("shipment not found")
+	}
+	sh := &o.Shipments[idx]
+	if req.Status != entity.ShipmentStatusDelivered || sh.Status != entity.ShipmentStatusShipped {
+		return nil, fmt.Errorf("cannot change shipment status from %s to %s", sh.Status, req.Status)
+	}
+	if err := s.deliverShipment(ctx, o, idx, time.Now()); err != nil {
+		return nil, err
+	}
+
+	res := toOrderResponse(*o)
+	return &res, nil
+}
+
+// deliverShipment marks the shipment at idx of o delivered at the given time
+// and moves the order to the status derived from its shipments.
+func (s *orderService) deliverShipment(ctx context.Context, o *entity.Order, idx int, at time.Time) error {
+	sh := &o.Shipments[idx]
+	sh.Status = entity.ShipmentStatusDelivered
+	sh.DeliveredAt = &at
+	status := deriveOrderStatus(*o)
+	if err := s.repo.ShipmentRepo.UpdateShipmentStatus(ctx, sh, status); err != nil {
+		return err
+	}
+	o.Status = status
+	return nil
+}
+
+// bookShipment books sh with its carrier and returns the tracking number.
+func (s *orderService) bookShipment(ctx context.Context, o entity.Order, sh entity.Shipment) (string, error) {
+	carrier, err := s.carriers.Get(sh.Carrier)
+	if err != nil {
+		return "", err
+	}
+	weights := make(map[uint]int, len(o.Items))
+	for _, it := range o.Items {
+		weights[it.ID] = it.Weight
+	}
+	req := shipping.ShipmentRequest{
+		Reference: utils.Deref(o.OrderNumber),
+		Name:      o.ShippingName,
+		Address:   o.ShippingAddress,
+	}
+	for _, it := range sh.Items {
+		req.Weight += weights[it.OrderItemID] * it.Quantity
+	}
+	label, err := carrier.CreateShipment(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to book shipment with %s: %w", sh.Carrier, err)
+	}
+	return label.TrackingNumber, nil
+}
+
+// remainingToShip maps each order item id to the quantity not in any shipment yet.
+func remainingToShip(o entity.Order) map[uint]int {
+	remaining := make(map[uint]int, len(o.Items))
+	for _, it := range o.Items {
+		remaining[it.ID] = it.Quantity
+	}
+	for _, sh := range o.Shipments {
+		for _, it := range sh.Items {
+			remaining[it.OrderItemID] -= it.Quantity
+		}
+	}
+	return remaining
+}
+
+// deriveOrderStatus computes the fulfillment status of o from its shipments:
+// partially shipped while items are left, delivered once every shipment is.
+func deriveOrderStatus(o entity.Order) string {
+	if len(o.Shipments) == 0 {
+		return o.Status
+	}
+	for _, left := range remainingToShip(o) {
+		if left > 0 {
+			return entity.OrderStatusPartiallyShipped
+		}
+	}
+	for _, sh := range o.Shipments {
+		if sh.Status != entity.ShipmentStatusDelivered {
+			return entity.OrderStatusShipped
+		}
+	}
+	return entity.OrderStatusDelivered
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+// Shipment repo writing straight into the order served by fixedOrderRepo
+type memShipmentRepo struct {
+	order *entity.Order
+}
+
+func (r *memShipmentRepo) CreateShipment(ctx context.Context, sh *entity.Shipment, orderStatus string) error {
+	sh.ID = uint(len(r.order.Shipments) + 1)
+	r.order.Shipments = append(r.order.Shipments, *sh)
+	r.order.Status = orderStatus
+	return nil
+}
+
+func (r *memShipmentRepo) GetShipmentByID(ctx context.Context, id uint) (*entity.Shipment, error) {
+	return &r.order.Shipments[id-1], nil
+}
+
+func (r *memShipmentRepo) UpdateShipmentStatus(ctx context.Context, sh *entity.Shipment, orderStatus string) error {
+	r.order.Shipments[sh.ID-1] = *sh
+	r.order.Status = orderStatus
+	return nil
+}
+
+func (r *memShipmentRepo) ListTrackableShipments(ctx context.Context, carriers []string, afterID uint, limit int) ([]entity.Shipment, error) {
+	var out []entity.Shipment
+	for _, sh := range r.order.Shipments {
+		if sh.Status == entity.ShipmentStatusShipped && sh.ID > afterID && slices.Contains(carriers, sh.Carrier) {
+			out = append(out, sh)
+		}
+	}
+	return out, nil
+}
+
+func (r *memShipmentRepo) AddTrackingEvents(ctx context.Context, sh *entity.Shipment, events []entity.ShipmentEvent) error {
+	stored := &r.order.Shipments[sh.ID-1]
+	stored.TrackingStatus = sh.TrackingStatus
+	stored.Events = append(stored.Events, events...)
+	return nil
+}
+
+func shipmentTestService(order *entity.Order) *orderService {
+	// the service works on copies, the repos on the stored order
+	repoVal := repository.Repository{OrderRepo: &copyingOrderRepo{order: order}, ShipmentRepo: &memShipmentRepo{order: order}}
+	return &orderService{repo: repoVal, logger: zap.NewNop()}
+}
+
+// Order repo returning a fresh copy of the stored order on every read
+type copyingOrderRepo struct {
+	simpleOrderRepo
+	order *entity.Order
+}
+
+func (r *copyingOrderRepo) GetOrderByID(ctx context.Context, id uint) (*entity.Order, error) {
+	o := *r.order
+	o.Shipments = append([]entity.Shipment(nil), r.order.Shipments...)
+	return &o, nil
+}
+
+func TestShipments_PartialThenDelivered(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, Status: entity.OrderStatusProcessing, Items: []entity.OrderItem{
+		{Model: entity.Model{ID: 10}, Quantity: 2},
+		{Model: entity.Model{ID: 11}, Quantity: 1},
+	}}
+	svc := shipmentTestService(order)
+	ctx := context.Background()
+
+	res, err := svc.CreateShipment(ctx, 1, dto.CreateShipmentRequest{TrackingNumber: "JNE1", Items: []dto.ShipmentItemRequest{{OrderItemID: 10, Quantity: 2}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusPartiallyShipped || order.Status != entity.OrderStatusPartiallyShipped {
+		t.Fatalf("expected partially shipped, got %s", res.Status)
+	}
+
+	if _, err := svc.CreateShipment(ctx, 1, dto.CreateShipmentRequest{TrackingNumber: "JNE2", Items: []dto.ShipmentItemRequest{{OrderItemID: 10, Quantity: 1}}}); err == nil {
+		t.Fatalf("expected error shipping more than ordered")
+	}
+
+	// no items ships whatever is left
+	res, err = svc.CreateShipment(ctx, 1, dto.CreateShipmentRequest{TrackingNumber: "JNE2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusShipped || len(res.Shipments) != 2 || res.Shipments[1].Items[0].OrderItemID != 11 {
+		t.Fatalf("expected shipped with the remaining item, got %+v", res)
+	}
+
+	if res, _ = svc.UpdateShipmentStatus(ctx, 1, 1, dto.UpdateShipmentStatusRequest{Status: entity.ShipmentStatusDelivered}); res.Status != entity.OrderStatusShipped {
+		t.Fatalf("order is delivered only once every shipment is, got %s", res.Status)
+	}
+	if res, _ = svc.UpdateShipmentStatus(ctx, 1, 2, dto.UpdateShipmentStatusRequest{Status: entity.ShipmentStatusDelivered}); res.Status != entity.OrderStatusDelivered {
+		t.Fatalf("expected delivered, got %s", res.Status)
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+const trackingBatchSize = 100
+
+// SyncShipmentTracking polls the carriers of shipments still on their way,
+// stores the tracking events they report and marks shipments delivered once
+// their carrier says so. It returns how many shipments were delivered.
+func (s *orderService) SyncShipmentTracking(ctx context.Context) (int, error) {
+	carriers := s.carriers.Names()
+	if len(carriers) == 0 {
+		return 0, nil
+	}
+	delivered := 0
+	var afterID uint
+	for {
+		shipments, err := s.repo.ShipmentRepo.ListTrackableShipments(ctx, carriers, afterID, trackingBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+		for i := range shipments {
+			sh := &shipments[i]
+			afterID = sh.ID
+			done, err := s.trackShipment(ctx, sh)
+			if err != nil {
+				// one carrier being down must not hold up the others
+				s.logger.Warn("failed to track shipment", zap.Uint("shipment_id", sh.ID), zap.String("carrier", sh.Carrier), zap.Error(err))
+				continue
+			}
+			if done {
+				delivered++
+			}
+		}
+		if len(shipments) < trackingBatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// trackShipment stores the new tracking events of sh and reports whether it
+// was delivered.
+func (s *orderService) trackShipment(ctx context.Context, sh *entity.Shipment) (bool, error) {
+	carrier, err := s.carriers.Get(sh.Carrier)
+	if err != nil {
+		return false, err
+	}
+	events, err := carrier.GetTrackingEvents(ctx, sh.TrackingNumber)
+	if err != nil {
+		return false, err
+	}
+	if len(events) == 0 {
+		return false, nil
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+
+	known := make(map[string]bool, len(sh.Events))
+	for _, ev := range sh.Events {
+		known[ev.EventID] = true
+	}
+	var fresh []entity.ShipmentEvent
+	for _, ev := range events {
+		if !known[ev.ID] {
+			fresh = append(fresh, entity.ShipmentEvent{
+				ShipmentID:  sh.ID,
+				EventID:     ev.ID,
+				Status:      string(ev.Status),
+				Description: ev.Description,
+				Location:    ev.Location,
+				OccurredAt:  ev.OccurredAt,
+			})
+		}
+	}
+	last := events[len(events)-1]
+	if len(fresh) > 0 {
+		sh.TrackingStatus = string(last.Status)
+		if err := s.repo.ShipmentRepo.AddTrackingEvents(ctx, sh, fresh); err != nil {
+			return false, err
+		}
+	}
+	if last.Status != shipping.StatusDelivered {
+		return false, nil
+	}
+
+	// the order holds the other shipments the order status depends on
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, sh.OrderID)
+	if err != nil {
+		return false, err
+	}
+	for i := range o.Shipments {
+		if o.Shipments[i].ID == sh.ID && o.Shipments[i].Status == entity.ShipmentStatusShipped {
+			if err := s.deliverShipment(ctx, o, i, last.OccurredAt); err != nil {
+				return false, err
+			}
+			s.logger.Info("shipment delivered", zap.Uint("order_id", o.ID), zap.Uint("shipment_id", sh.ID), zap.String("order_status", o.Status))
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
+)
+
+func TestSyncShipmentTracking_AdvancesOrderToDelivered(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, Status: entity.OrderStatusProcessing, Items: []entity.OrderItem{
+		{Model: entity.Model{ID: 10}, Quantity: 1, Weight: 300},
+		{Model: entity.Model{ID: 11}, Quantity: 1, Weight: 200},
+	}}
+	carrier := shipping.NewMockCarrier(time.Hour)
+	svc := shipmentTestService(order)
+	svc.carriers = shipping.NewRegistry()
+	svc.carriers.Register("mock", carrier)
+	ctx := context.Background()
+
+	res, err := svc.CreateShipment(ctx, 1, dto.CreateShipmentRequest{Carrier: "mock", Items: []dto.ShipmentItemRequest{{OrderItemID: 10, Quantity: 1}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := res.Shipments[0].TrackingNumber
+	if first == "" {
+		t.Fatalf("expected the carrier to assign a tracking number")
+	}
+	if _, err := svc.CreateShipment(ctx, 1, dto.CreateShipmentRequest{Carrier: "mock", TrackingNumber: "MANUAL1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	carrier.Track("MANUAL1", time.Now().Add(-4*time.Hour))
+
+	n, err := svc.SyncShipmentTracking(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one shipment delivered, got %d, %v", n, err)
+	}
+	if order.Status != entity.OrderStatusShipped || order.Shipments[1].Status != entity.ShipmentStatusDelivered || len(order.Shipments[1].Events) != 4 {
+		t.Fatalf("expected only the manual shipment delivered, got %+v", order)
+	}
+	if order.Shipments[0].TrackingStatus != string(shipping.StatusPickedUp) || len(order.Shipments[0].Events) != 1 {
+		t.Fatalf("expected the booked shipment to be picked up, got %+v", order.Shipments[0])
+	}
+
+	carrier.Track(first, time.Now().Add(-3*time.Hour))
+	if n, err = svc.SyncShipmentTracking(ctx); err != nil || n != 1 {
+		t.Fatalf("expected the booked shipment delivered, got %d, %v", n, err)
+	}
+	if order.Status != entity.OrderStatusDelivered || len(order.Shipments[0].Events) != 4 || order.Shipments[0].DeliveredAt == nil {
+		t.Fatalf("expected the order delivered without duplicate events, got %+v", order)
+	}
+}
+
+func TestCreateShipment_UnknownCarrierNeedsTrackingNumber(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, Status: entity.OrderStatusProcessing, Items: []entity.OrderItem{{Model: entity.Model{ID: 10}, Quantity: 1}}}
+	svc := shipmentTestService(order)
+	svc.carriers = shipping.NewRegistry()
+
+	_, err := svc.CreateShipment(context.Background(), 1, dto.CreateShipmentRequest{Carrier: "JNE"})
+	if err == nil || err.Error() != "carrier JNE is not available" {
+		t.Fatalf("expected an unregistered carrier to be rejected, got %v", err)
+	}
+	if len(order.Shipments) != 0 {
+		t.Fatalf("expected no shipment to be stored")
+	}
+}
@@ -14,11 +14,11 @@ import (
 
 // Test that voucher with zero usage limit is rejected
 func TestCreateOrder_VoucherUsageLimitZero(t *testing.T){
-	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, Quantity:1, UnitPrice:100}}}
+	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, ProductVariant: availableVariant(1), Quantity:1, UnitPrice:100}}}
 	promo := &entity.Promotion{Model: entity.Model{ID:2}, Type: "fixed", Discount: 10, StartDate: time.Now().Add(-time.Hour), EndDate: time.Now().Add(time.Hour), Published:true, UsageLimit:0}
-	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart:cart}, PromotionRepo: &simplePromoRepo{promo:promo}, OrderRepo: &simpleOrderRepo{}, AddressRepo: nil}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart:cart}, PromotionRepo: &simplePromoRepo{promo:promo}, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
 	logger, _ := zap.NewDevelopment()
-	svc := &orderService{repo: repoVal, logger: logger}
+	svc := &orderService{repo: repoVal, logger: logger, payments: testPayments()}
 	code := "PROMOZERO"
 	promo.VoucherCode = &code
 	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
@@ -29,6 +29,7 @@ func TestCreateOrder_VoucherUsageLimitZero(t *testing.T){
 
 // Test that DecrementUsage is called (we simulate by using a promo repo that records calls)
 type trackingPromoRepo struct{
+	simplePromoRepo
 	promo *entity.Promotion
 	called bool
 }
@@ -37,14 +38,15 @@ func (r *trackingPromoRepo) GetByVoucherCode(ctx context.Context, code string) (
 	return r.promo, nil
 }
 func (r *trackingPromoRepo) DecrementUsage(ctx context.Context, id uint) error { r.called = true; return nil }
+func (r *trackingPromoRepo) RestoreUsage(ctx context.Context, id uint) error { return nil }
 
 func TestCreateOrder_DecrementUsageCalled(t *testing.T){
-	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, Quantity:1, UnitPrice:100}}}
+	cart := &entity.Cart{CustomerID:1, Items: []entity.CartItem{{ProductVariantID:1, ProductVariant: availableVariant(1), Quantity:1, UnitPrice:100}}}
 	promo := &entity.Promotion{Model: entity.Model{ID:3}, Type: "fixed", Discount: 10, StartDate: time.Now().Add(-time.Hour), EndDate: time.Now().Add(time.Hour), Published:true, UsageLimit:5}
 	repoPromo := &trackingPromoRepo{promo: promo}
-	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart:cart}, PromotionRepo: repoPromo, OrderRepo: &simpleOrderRepo{}, AddressRepo: nil}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart:cart}, PromotionRepo: repoPromo, OrderRepo: &simpleOrderRepo{}, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
 	logger, _ := zap.NewDevelopment()
-	svc := &orderService{repo: repoVal, logger: logger}
+	svc := &orderService{repo: repoVal, logger: logger, payments: testPayments()}
 	code := "PROMOTRACK"
 	promo.VoucherCode = &code
 	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
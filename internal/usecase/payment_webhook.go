@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+
+	"go.uber.org/zap"
+)
+
+// HandlePaymentWebhook verifies and applies a webhook sent by the provider of
+// method. Every request is logged raw; events that were applied before are
+// acknowledged without being applied again.
+func (s *orderService) HandlePaymentWebhook(ctx context.Context, method string, header http.Header, body []byte) (err error) {
+	headers, _ := json.Marshal(header)
+	l := &entity.PaymentWebhookLog{Method: method, Headers: string(headers), Payload: string(body)}
+	defer func() {
+		if err != nil {
+			l.Error = err.Error()
+			if l.Result == "" {
+				l.Result = "rejected"
+			}
+		}
+		if lerr := s.repo.PaymentRepo.CreateWebhookLog(ctx, l); lerr != nil {
+			s.logger.Error("failed to log payment webhook", zap.String("method", method), zap.ByteString("payload", body), zap.Error(lerr))
+		}
+	}()
+
+	provider, err := s.payments.Get(method)
+	if err != nil {
+		return err
+	}
+	ev, err := provider.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+	l.EventID = ev.ID
+
+	rec := &entity.PaymentEvent{Method: method, EventID: ev.ID, ChargeID: ev.Charge.ID, Status: string(ev.Charge.Status)}
+	created, err := s.repo.PaymentRepo.RecordEvent(ctx, rec)
+	if err != nil {
+		l.Result = "failed"
+		return err
+	}
+	if !created {
+		l.Result = "duplicate"
+		return nil
+	}
+
+	if err := s.applyWebhookCharge(ctx, &ev.Charge); err != nil {
+		// forget the event so the provider's retry gets applied
+		if derr := s.repo.PaymentRepo.DeleteEvent(ctx, rec.ID); derr != nil {
+			s.logger.Error("failed to forget payment event", zap.String("event_id", ev.ID), zap.Error(derr))
+		}
+		l.Result = "failed"
+		return err
+	}
+	l.Result = "processed"
+	return nil
+}
+
+func (s *orderService) applyWebhookCharge(ctx context.Context, ch *payment.Charge) error {
+	p, err := s.repo.PaymentRepo.GetPaymentByChargeID(ctx, ch.ID)
+	if err != nil {
+		return fmt.Errorf("unknown charge %s", ch.ID)
+	}
+	o, err := s.repo.OrderRepo.GetOrderByID(ctx, p.OrderID)
+	if err != nil {
+		return err
+	}
+	return s.applyCharge(ctx, o, p, ch)
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+)
+
+func TestHandlePaymentWebhook_AppliesEventOnce(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 80, Status: entity.OrderStatusCreated}
+	svc, mock, repo := paymentTestService(order)
+	ctx := context.Background()
+
+	started, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := mock.Complete(started.ChargeID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	header, body, err := mock.Webhook(started.ChargeID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := svc.HandlePaymentWebhook(ctx, "gopay", header, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != entity.OrderStatusPaid || repo.payments[0].Status != string(payment.StatusPaid) {
+		t.Fatalf("expected paid order, got %s", order.Status)
+	}
+
+	// redelivery of the same event is acknowledged but not applied again
+	if err := svc.HandlePaymentWebhook(ctx, "gopay", header, body); err != nil {
+		t.Fatalf("redelivery must be acknowledged, got %v", err)
+	}
+	if len(repo.events) != 1 || len(repo.logs) != 2 || repo.logs[1].Result != "duplicate" {
+		t.Fatalf("expected one event and a duplicate log, got %d events, logs %+v", len(repo.events), repo.logs)
+	}
+}
+
+func TestHandlePaymentWebhook_RejectsBadSignature(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 80, Status: entity.OrderStatusCreated}
+	svc, mock, repo := paymentTestService(order)
+	ctx := context.Background()
+
+	started, _ := svc.PayOrder(ctx, 1, 1)
+	_, _ = mock.Complete(started.ChargeID)
+	_, body, _ := mock.Webhook(started.ChargeID)
+	header := http.Header{}
+	header.Set(payment.MockSignatureHeader, payment.SignHMAC("wrong-secret", body))
+
+	err := svc.HandlePaymentWebhook(ctx, "gopay", header, body)
+	if !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+	if order.Status != entity.OrderStatusCreated {
+		t.Fatalf("forged webhook must not change the order, got %s", order.Status)
+	}
+	if len(repo.logs) != 1 || repo.logs[0].Result != "rejected" || repo.logs[0].Payload != string(body) {
+		t.Fatalf("expected the raw payload to be logged as rejected, got %+v", repo.logs)
+	}
+}
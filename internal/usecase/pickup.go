@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// PickupService manages the stores customers can collect orders from.
+type PickupService interface {
+	ListLocations(ctx context.Context, all bool) ([]dto.PickupLocationResponse, error)
+	CreateLocation(ctx context.Context, req dto.PickupLocationRequest) (*dto.PickupLocationResponse, error)
+	UpdateLocation(ctx context.Context, id uint, req dto.PickupLocationRequest) (*dto.PickupLocationResponse, error)
+	DeleteLocation(ctx context.Context, id uint) error
+}
+
+type pickupService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewPickupService(repo repository.Repository, logger *zap.Logger) PickupService {
+	return &pickupService{repo: repo, logger: logger}
+}
+
+// ListLocations returns the active locations, or every location when all is set.
+func (s *pickupService) ListLocations(ctx context.Context, all bool) ([]dto.PickupLocationResponse, error) {
+	locations, err := s.repo.PickupRepo.ListLocations(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.PickupLocationResponse, 0, len(locations))
+	for _, l := range locations {
+		res = append(res, toPickupLocationResponse(l))
+	}
+	return res, nil
+}
+
+func (s *pickupService) CreateLocation(ctx context.Context, req dto.PickupLocationRequest) (*dto.PickupLocationResponse, error) {
+	l := &entity.PickupLocation{Active: true}
+	applyPickupLocationRequest(l, req)
+	if err := s.repo.PickupRepo.SaveLocation(ctx, l); err != nil {
+		return nil, err
+	}
+	res := toPickupLocationResponse(*l)
+	return &res, nil
+}
+
+func (s *pickupService) UpdateLocation(ctx context.Context, id uint, req dto.PickupLocationRequest) (*dto.PickupLocationResponse, error) {
+	l, err := s.repo.PickupRepo.GetLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyPickupLocationRequest(l, req)
+	if err := s.repo.PickupRepo.SaveLocation(ctx, l); err != nil {
+		return nil, err
+	}
+	res := toPickupLocationResponse(*l)
+	return &res, nil
+}
+
+// DeleteLocation removes a location. Orders keep the snapshot of its address;
+// deactivate it instead to keep it listed in the admin.
+func (s *pickupService) DeleteLocation(ctx context.Context, id uint) error {
+	if _, err := s.repo.PickupRepo.GetLocation(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.PickupRepo.DeleteLocation(ctx, id)
+}
+
+func applyPickupLocationRequest(l *entity.PickupLocation, req dto.PickupLocationRequest) {
+	l.Name = strings.TrimSpace(req.Name)
+	l.Address = strings.TrimSpace(req.Address)
+	l.Phone = strings.TrimSpace(req.Phone)
+	l.OpeningHours = strings.TrimSpace(req.OpeningHours)
+	if req.Active != nil {
+		l.Active = *req.Active
+	}
+}
+
+func toPickupLocationResponse(l entity.PickupLocation) dto.PickupLocationResponse {
+	return dto.PickupLocationResponse{
+		ID:           l.ID,
+		Name:         l.Name,
+		Address:      l.Address,
+		Phone:        l.Phone,
+		OpeningHours: l.OpeningHours,
+		Active:       l.Active,
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"math"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+// orderTotals is the monetary breakdown persisted on an order.
+type orderTotals struct {
+	Subtotal    float64
+	Discount    float64
+	ShippingFee float64
+	Tax         float64
+	GrandTotal  float64
+}
+
+// orderPricer computes order totals once, so every order endpoint returns
+// the same numbers that were charged at checkout.
+type orderPricer struct {
+	taxRate float64 // percent, applied to the discounted subtotal
+}
+
+func newOrderPricer(taxRate float64) orderPricer {
+	return orderPricer{taxRate: taxRate}
+}
+
+// Price totals items with the discount worked out by the promotion rules,
+// never more than the subtotal.
+func (p orderPricer) Price(items []entity.OrderItem, discount float64, shippingFee float64) orderTotals {
+	var t orderTotals
+	for _, it := range items {
+		t.Subtotal += float64(it.Quantity) * it.UnitPrice
+	}
+	t.Subtotal = roundMoney(t.Subtotal)
+
+	t.Discount = discount
+	if t.Discount < 0 {
+		t.Discount = 0
+	}
+	if t.Discount > t.Subtotal {
+		t.Discount = t.Subtotal
+	}
+	t.Discount = roundMoney(t.Discount)
+
+	t.ShippingFee = roundMoney(shippingFee)
+	t.Tax = roundMoney((t.Subtotal - t.Discount) * p.taxRate / 100.0)
+	t.GrandTotal = roundMoney(t.Subtotal - t.Discount + t.ShippingFee + t.Tax)
+	return t
+}
+
+func (t orderTotals) applyTo(o *entity.Order) {
+	o.Subtotal = t.Subtotal
+	o.Discount = t.Discount
+	o.ShippingFee = t.ShippingFee
+	o.Tax = t.Tax
+	o.GrandTotal = t.GrandTotal
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
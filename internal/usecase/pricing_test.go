@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"testing"
+
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+)
+
+func TestOrderPricer_Price(t *testing.T) {
+	items := []entity.OrderItem{{Quantity: 2, UnitPrice: 50}, {Quantity: 1, UnitPrice: 100}}
+	cases := []struct {
+		name     string
+		discount float64
+		want     orderTotals
+	}{
+		{"no discount", 0, orderTotals{Subtotal: 200, Tax: 22, GrandTotal: 232, ShippingFee: 10}},
+		{"discount", 20, orderTotals{Subtotal: 200, Discount: 20, Tax: 19.8, ShippingFee: 10, GrandTotal: 209.8}},
+		{"capped at subtotal", 500, orderTotals{Subtotal: 200, Discount: 200, ShippingFee: 10, GrandTotal: 10}},
+	}
+	p := newOrderPricer(11)
+	for _, c := range cases {
+		got := p.Price(items, c.discount, 10)
+		if got != c.want {
+			t.Fatalf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestOrderTotals_PersistedOnOrder(t *testing.T) {
+	o := &entity.Order{}
+	newOrderPricer(0).Price([]entity.OrderItem{{Quantity: 3, UnitPrice: 10}}, 0, 0).applyTo(o)
+	if o.Subtotal != 30 || o.GrandTotal != 30 {
+		t.Fatalf("unexpected totals on order: %+v", o)
+	}
+	if res := toOrderResponse(*o); res.Total != o.GrandTotal {
+		t.Fatalf("response total %v does not match persisted grand total %v", res.Total, o.GrandTotal)
+	}
+}
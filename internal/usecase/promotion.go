@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// PromotionService lets admins manage promotions and the products and
+// categories they apply to.
+type PromotionService interface {
+	ListPromotions(ctx context.Context, q dto.PromotionListQuery) (*dto.PromotionListResponse, error)
+	GetPromotion(ctx context.Context, id uint) (*dto.PromotionResponse, error)
+	CreatePromotion(ctx context.Context, req dto.PromotionRequest) (*dto.PromotionResponse, error)
+	UpdatePromotion(ctx context.Context, id uint, req dto.PromotionRequest) (*dto.PromotionResponse, error)
+	DeletePromotion(ctx context.Context, id uint) error
+	TogglePublished(ctx context.Context, id uint, published bool) error
+	AttachProducts(ctx context.Context, id uint, req dto.PromotionProductsRequest) (*dto.PromotionResponse, error)
+	DetachProduct(ctx context.Context, id, productID uint) (*dto.PromotionResponse, error)
+	AttachCategories(ctx context.Context, id uint, req dto.PromotionCategoriesRequest) (*dto.PromotionResponse, error)
+	DetachCategory(ctx context.Context, id, categoryID uint) (*dto.PromotionResponse, error)
+}
+
+type promotionService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewPromotionService(repo repository.Repository, logger *zap.Logger) PromotionService {
+	return &promotionService{repo: repo, logger: logger}
+}
+
+func (s *promotionService) ListPromotions(ctx context.Context, q dto.PromotionListQuery) (*dto.PromotionListResponse, error) {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	promos, total, err := s.repo.PromotionRepo.ListPromotions(ctx, q.Page, q.Limit, q.Search)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.PromotionResponse, 0, len(promos))
+	for _, p := range promos {
+		items = append(items, toPromotionResponse(p))
+	}
+	return &dto.PromotionListResponse{
+		Items:        items,
+		CurrentPage:  q.Page,
+		Limit:        q.Limit,
+		TotalPages:   int((total + int64(q.Limit) - 1) / int64(q.Limit)),
+		TotalRecords: total,
+	}, nil
+}
+
+func (s *promotionService) GetPromotion(ctx context.Context, id uint) (*dto.PromotionResponse, error) {
+	p, err := s.repo.PromotionRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res := toPromotionResponse(*p)
+	return &res, nil
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, req dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	p := &entity.Promotion{}
+	if err := s.applyPromotionRequest(ctx, p, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.PromotionRepo.CreatePromotion(ctx, p); err != nil {
+		return nil, err
+	}
+	res := toPromotionResponse(*p)
+	return &res, nil
+}
+
+// UpdatePromotion replaces the promotion's settings; attached products and
+// categories are kept.
+func (s *promotionService) UpdatePromotion(ctx context.Context, id uint, req dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	p, err := s.repo.PromotionRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPromotionRequest(ctx, p, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.PromotionRepo.UpdatePromotion(ctx, p); err != nil {
+		return nil, err
+	}
+	res := toPromotionResponse(*p)
+	return &res, nil
+}
+
+func (s *promotionService) DeletePromotion(ctx context.Context, id uint) error {
+	if _, err := s.repo.PromotionRepo.GetPromotionByID(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.PromotionRepo.DeletePromotion(ctx, id)
+}
+
+func (s *promotionService) TogglePublished(ctx context.Context, id uint, published bool) error {
+	if _, err := s.repo.PromotionRepo.GetPromotionByID(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.PromotionRepo.TogglePublished(ctx, id, published)
+}
+
+func (s *promotionService) AttachProducts(ctx context.Context, id uint, req dto.PromotionProductsRequest) (*dto.PromotionResponse, error) {
+	if _, err := s.repo.PromotionRepo.GetPromotionByID(ctx, id); err != nil {
+		return nil, err
+	}
+	found, err := s.repo.PromotionRepo.ExistingProductIDs(ctx, req.ProductIDs)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[uint]bool, len(found))
+	for _, pid := range found {
+		exists[pid] = true
+	}
+	for _, pid := range req.ProductIDs {
+		if !exists[pid] {
+			return nil, fmt.Errorf("product %d not found", pid)
+		}
+	}
+	if err := s.repo.PromotionRepo.AttachProducts(ctx, id, found); err != nil {
+		return nil, err
+	}
+	return s.GetPromotion(ctx, id)
+}
+
+func (s *promotionService) DetachProduct(ctx context.Context, id, productID uint) (*dto.PromotionResponse, error) {
+	if err := s.repo.PromotionRepo.DetachProduct(ctx, id, productID); err != nil {
+		return nil, err
+	}
+	return s.GetPromotion(ctx, id)
+}
+
+func (s *promotionService) AttachCategories(ctx context.Context, id uint, req dto.PromotionCategoriesRequest) (*dto.PromotionResponse, error) {
+	if _, err := s.repo.PromotionRepo.GetPromotionByID(ctx, id); err != nil {
+		return nil, err
+	}
+	found, err := s.repo.PromotionRepo.ExistingCategoryIDs(ctx, req.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[uint]bool, len(found))
+	for _, cid := range found {
+		exists[cid] = true
+	}
+	for _, cid := range req.CategoryIDs {
+		if !exists[cid] {
+			return nil, fmt.Errorf("category %d not found", cid)
+		}
+	}
+	if err := s.repo.PromotionRepo.AttachCategories(ctx, id, found); err != nil {
+		return nil, err
+	}
+	return s.GetPromotion(ctx, id)
+}
+
+func (s *promotionService) DetachCategory(ctx context.Context, id, categoryID uint) (*dto.PromotionResponse, error) {
+	if err := s.repo.PromotionRepo.DetachCategory(ctx, id, categoryID); err != nil {
+		return nil, err
+	}
+	return s.GetPromotion(ctx, id)
+}
+
+// applyPromotionRequest validates req and copies it onto p. Voucher codes are
+// stored upper case and must be unique; an empty code removes it.
+func (s *promotionService) applyPromotionRequest(ctx context.Context, p *entity.Promotion, req dto.PromotionRequest) error {
+	if !req.EndDate.After(req.StartDate) {
+		return errors.New("end_date must be after start_date")
+	}
+	if req.Type == entity.PromotionTypePercentage && req.Discount > 100 {
+		return errors.New("a percentage discount cannot exceed 100")
+	}
+	if req.Type == entity.PromotionTypeFixed && req.MaxDiscount > 0 && req.MaxDiscount < req.Discount {
+		return errors.New("max_discount cannot be lower than the discount")
+	}
+	p.VoucherCode = nil
+	if code := normalizeVoucherCode(req.VoucherCode); code != "" {
+		exists, err := s.repo.PromotionRepo.IsVoucherCodeExists(ctx, code, p.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("voucher code %s is already used by another promotion", code)
+		}
+		p.VoucherCode = &code
+	}
+	p.Name = strings.TrimSpace(req.Name)
+	p.Type = req.Type
+	p.StartDate = req.StartDate
+	p.EndDate = req.EndDate
+	p.Discount = req.Discount
+	p.MaxDiscount = req.MaxDiscount
+	p.MinSubtotal = req.MinSubtotal
+	p.FirstOrderOnly = req.FirstOrderOnly
+	p.Segment = req.Segment
+	methods := make([]string, 0, len(req.PaymentMethods))
+	for _, m := range req.PaymentMethods {
+		methods = append(methods, strings.ToLower(strings.TrimSpace(m)))
+	}
+	p.PaymentMethods = strings.Join(methods, ",")
+	p.UsageLimit = req.UsageLimit
+	p.ShowOnCheckout = req.ShowOnCheckout
+	p.Published = req.Published
+	return nil
+}
+
+// normalizeVoucherCode makes voucher codes case-insensitive.
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func toPromotionResponse(p entity.Promotion) dto.PromotionResponse {
+	products := make([]dto.PromotionProductResponse, 0, len(p.Products))
+	for _, pp := range p.Products {
+		products = append(products, dto.PromotionProductResponse{ProductID: pp.ProductID, Name: pp.Product.Name, SKU: pp.Product.SKU})
+	}
+	categories := make([]dto.PromotionCategoryResponse, 0, len(p.Categories))
+	for _, pc := range p.Categories {
+		categories = append(categories, dto.PromotionCategoryResponse{CategoryID: pc.CategoryID, Name: pc.Category.Name})
+	}
+	methods := promotionPaymentMethods(p)
+	if methods == nil {
+		methods = []string{}
+	}
+	return dto.PromotionResponse{
+		ID:             p.ID,
+		Name:           p.Name,
+		Type:           p.Type,
+		StartDate:      p.StartDate,
+		EndDate:        p.EndDate,
+		Discount:       p.Discount,
+		MaxDiscount:    p.MaxDiscount,
+		MinSubtotal:    p.MinSubtotal,
+		FirstOrderOnly: p.FirstOrderOnly,
+		Segment:        p.Segment,
+		PaymentMethods: methods,
+		UsageLimit:     p.UsageLimit,
+		VoucherCode:    utils.Deref(p.VoucherCode),
+		ShowOnCheckout: p.ShowOnCheckout,
+		Published:      p.Published,
+		Products:       products,
+		Categories:     categories,
+	}
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"strings"
+)
+
+// loyalCustomerOrders is how many placed orders make a customer loyal.
+const loyalCustomerOrders = 5
+
+// promotionLine is an order line as promotion rules see it.
+type promotionLine struct {
+	productID  uint
+	categoryID uint
+	quantity   int
+	unitPrice  float64
+}
+
+// promotionInput is everything promotion rules look at: the order lines and
+// who is checking out how. placedOrders is only loaded for promotions that
+// target first orders or a customer segment.
+type promotionInput struct {
+	lines         []promotionLine
+	paymentMethod string
+	placedOrders  int64
+}
+
+func (in promotionInput) subtotal() float64 {
+	var total float64
+	for _, l := range in.lines {
+		total += float64(l.quantity) * l.unitPrice
+	}
+	return roundMoney(total)
+}
+
+// promotionCondition returns why p does not apply to in, or "" when it does.
+type promotionCondition func(p entity.Promotion, in promotionInput) string
+
+// promotionAction computes the discount of p on the lines it applies to.
+type promotionAction func(p entity.Promotion, lines []promotionLine) float64
+
+// promotionConditions are checked in order; the first rejection is reported.
+var promotionConditions = []promotionCondition{
+	minSubtotalCondition,
+	scopeCondition,
+	firstOrderCondition,
+	segmentCondition,
+	paymentMethodCondition,
+}
+
+// promotionActions maps a promotion type to how its discount is computed.
+var promotionActions = map[string]promotionAction{
+	entity.PromotionTypePercentage: percentOffAction,
+	entity.PromotionTypeFixed:      fixedOffAction,
+	entity.PromotionTypePerItem:    perItemOffAction,
+}
+
+// evaluatePromotion returns the discount p gives on in, or why it does not
+// apply. The discount is capped at MaxDiscount and at the subtotal of the
+// lines it applies to.
+func evaluatePromotion(p entity.Promotion, in promotionInput) (float64, string) {
+	for _, cond := range promotionConditions {
+		if msg := cond(p, in); msg != "" {
+			return 0, msg
+		}
+	}
+	action, ok := promotionActions[p.Type]
+	if !ok {
+		return 0, "voucher cannot be applied to this order"
+	}
+	lines := eligibleLines(p, in.lines)
+	discount := action(p, lines)
+	if p.MaxDiscount > 0 && discount > p.MaxDiscount {
+		discount = p.MaxDiscount
+	}
+	eligible := promotionInput{lines: lines}.subtotal()
+	return roundMoney(math.Max(0, math.Min(discount, eligible))), ""
+}
+
+// eligibleLines returns the lines p applies to: all of them unless p is
+// limited to some products or categories.
+func eligibleLines(p entity.Promotion, lines []promotionLine) []promotionLine {
+	if len(p.Products) == 0 && len(p.Categories) == 0 {
+		return lines
+	}
+	products := make(map[uint]bool, len(p.Products))
+	for _, pp := range p.Products {
+		products[pp.ProductID] = true
+	}
+	categories := make(map[uint]bool, len(p.Categories))
+	for _, pc := range p.Categories {
+		categories[pc.CategoryID] = true
+	}
+	var eligible []promotionLine
+	for _, l := range lines {
+		if products[l.productID] || categories[l.categoryID] {
+			eligible = append(eligible, l)
+		}
+	}
+	return eligible
+}
+
+// customerSegment derives the segment of a customer from their placed orders.
+func customerSegment(placedOrders int64) string {
+	switch {
+	case placedOrders == 0:
+		return entity.CustomerSegmentNew
+	case placedOrders >= loyalCustomerOrders:
+		return entity.CustomerSegmentLoyal
+	}
+	return entity.CustomerSegmentReturning
+}
+
+// promotionPaymentMethods splits the comma separated methods p is limited to.
+func promotionPaymentMethods(p entity.Promotion) []string {
+	var methods []string
+	for _, m := range strings.Split(p.PaymentMethods, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+func minSubtotalCondition(p entity.Promotion, in promotionInput) string {
+	if in.subtotal() < p.MinSubtotal {
+		return fmt.Sprintf("spend at least %.2f to use this voucher", p.MinSubtotal)
+	}
+	return ""
+}
+
+func scopeCondition(p entity.Promotion, in promotionInput) string {
+	if len(eligibleLines(p, in.lines)) == 0 {
+		return "voucher does not apply to any product in your order"
+	}
+	return ""
+}
+
+func firstOrderCondition(p entity.Promotion, in promotionInput) string {
+	if p.FirstOrderOnly && in.placedOrders > 0 {
+		return "voucher is only valid on your first order"
+	}
+	return ""
+}
+
+func segmentCondition(p entity.Promotion, in promotionInput) string {
+	if p.Segment != "" && customerSegment(in.placedOrders) != p.Segment {
+		return fmt.Sprintf("voucher is only for %s customers", p.Segment)
+	}
+	return ""
+}
+
+func paymentMethodCondition(p entity.Promotion, in promotionInput) string {
+	methods := promotionPaymentMethods(p)
+	if len(methods) == 0 {
+		return ""
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, in.paymentMethod) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("voucher is only valid when paying with %s", strings.Join(methods, ", "))
+}
+
+func percentOffAction(p entity.Promotion, lines []promotionLine) float64 {
+	return promotionInput{lines: lines}.subtotal() * p.Discount / 100.0
+}
+
+func fixedOffAction(p entity.Promotion, lines []promotionLine) float64 {
+	return p.Discount
+}
+
+// perItemOffAction takes Discount off every unit, never more than its price.
+func perItemOffAction(p entity.Promotion, lines []promotionLine) float64 {
+	var discount float64
+	for _, l := range lines {
+		discount += float64(l.quantity) * math.Min(p.Discount, l.unitPrice)
+	}
+	return discount
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+func TestEvaluatePromotion(t *testing.T) {
+	// product 1 in category 10 (2 x 50), product 2 in category 20 (1 x 100)
+	lines := []promotionLine{{productID: 1, categoryID: 10, quantity: 2, unitPrice: 50}, {productID: 2, categoryID: 20, quantity: 1, unitPrice: 100}}
+	cases := []struct {
+		name   string
+		promo  entity.Promotion
+		in     promotionInput
+		want   float64
+		reject string
+	}{
+		{"percentage", entity.Promotion{Type: entity.PromotionTypePercentage, Discount: 10}, promotionInput{lines: lines}, 20, ""},
+		{"percentage capped", entity.Promotion{Type: entity.PromotionTypePercentage, Discount: 50, MaxDiscount: 30}, promotionInput{lines: lines}, 30, ""},
+		{"fixed capped at subtotal", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 500}, promotionInput{lines: lines}, 200, ""},
+		{"per item", entity.Promotion{Type: entity.PromotionTypePerItem, Discount: 5}, promotionInput{lines: lines}, 15, ""},
+		{"per item on a product", entity.Promotion{Type: entity.PromotionTypePerItem, Discount: 80, Products: []entity.PromotionProduct{{ProductID: 1}}}, promotionInput{lines: lines}, 100, ""},
+		{"percentage on a category", entity.Promotion{Type: entity.PromotionTypePercentage, Discount: 10, Categories: []entity.PromotionCategory{{CategoryID: 20}}}, promotionInput{lines: lines}, 10, ""},
+		{"min subtotal", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 10, MinSubtotal: 250}, promotionInput{lines: lines}, 0, "spend at least 250.00 to use this voucher"},
+		{"no matching product", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 10, Products: []entity.PromotionProduct{{ProductID: 3}}}, promotionInput{lines: lines}, 0, "voucher does not apply to any product in your order"},
+		{"first order", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 10, FirstOrderOnly: true}, promotionInput{lines: lines, placedOrders: 1}, 0, "voucher is only valid on your first order"},
+		{"loyal segment", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 10, Segment: entity.CustomerSegmentLoyal}, promotionInput{lines: lines, placedOrders: 2}, 0, "voucher is only for loyal customers"},
+		{"loyal customer", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 10, Segment: entity.CustomerSegmentLoyal}, promotionInput{lines: lines, placedOrders: 5}, 10, ""},
+		{"payment method", entity.Promotion{Type: entity.PromotionTypeFixed, Discount: 10, PaymentMethods: "gopay,ovo"}, promotionInput{lines: lines, paymentMethod: "cod"}, 0, "voucher is only valid when paying with gopay, ovo"},
+		{"unknown type", entity.Promotion{Type: "bogo", Discount: 10}, promotionInput{lines: lines}, 0, "voucher cannot be applied to this order"},
+	}
+	for _, c := range cases {
+		got, reject := evaluatePromotion(c.promo, c.in)
+		if got != c.want || reject != c.reject {
+			t.Fatalf("%s: got %v %q, want %v %q", c.name, got, reject, c.want, c.reject)
+		}
+	}
+}
+
+// Order repo for a customer who placed n orders before
+type placedOrderRepo struct {
+	simpleOrderRepo
+	n int64
+}
+
+func (r *placedOrderRepo) CountPlacedOrders(ctx context.Context, customerID uint) (int64, error) {
+	return r.n, nil
+}
+
+func TestCheckout_VoucherRules(t *testing.T) {
+	shirt := availableVariant(1)
+	shirt.ProductID, shirt.Product.CategoryID = 1, 10
+	mug := availableVariant(2)
+	mug.ProductID, mug.Product.CategoryID = 2, 20
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{
+		{ProductVariantID: 1, ProductVariant: shirt, Quantity: 2, UnitPrice: 50},
+		{ProductVariantID: 2, ProductVariant: mug, Quantity: 1, UnitPrice: 100},
+	}}
+	code := "WELCOME"
+	promo := &entity.Promotion{Model: entity.Model{ID: 1}, Type: entity.PromotionTypePercentage, Discount: 20, VoucherCode: &code, FirstOrderOnly: true,
+		StartDate: time.Now().Add(-time.Hour), EndDate: time.Now().Add(time.Hour), Published: true, UsageLimit: 5,
+		Categories: []entity.PromotionCategory{{CategoryID: 10}}}
+	orders := &placedOrderRepo{}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{promo: promo}, OrderRepo: orders, AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code}
+
+	q, err := svc.QuoteOrder(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !q.Valid || q.Subtotal != 200 || q.Discount != 20 {
+		t.Fatalf("expected 20%% off the shirts only, got %+v", q)
+	}
+
+	orders.n = 1
+	q, _ = svc.QuoteOrder(context.Background(), req, 1)
+	if q.Valid || q.Errors[0] != "voucher is only valid on your first order" || q.Discount != 0 {
+		t.Fatalf("expected the voucher to be rejected after the first order, got %+v", q)
+	}
+}
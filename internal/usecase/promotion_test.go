@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+// Promotions kept in memory; products 1 and 2 exist
+type memPromotionRepo struct {
+	simplePromoRepo
+	promos []*entity.Promotion
+}
+
+func (r *memPromotionRepo) GetPromotionByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	for _, p := range r.promos {
+		if p.ID == id {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memPromotionRepo) CreatePromotion(ctx context.Context, p *entity.Promotion) error {
+	p.ID = uint(len(r.promos) + 1)
+	cp := *p
+	r.promos = append(r.promos, &cp)
+	return nil
+}
+
+func (r *memPromotionRepo) UpdatePromotion(ctx context.Context, p *entity.Promotion) error {
+	cp := *p
+	r.promos[p.ID-1] = &cp
+	return nil
+}
+
+func (r *memPromotionRepo) IsVoucherCodeExists(ctx context.Context, code string, excludeID uint) (bool, error) {
+	for _, p := range r.promos {
+		if p.ID != excludeID && p.VoucherCode != nil && *p.VoucherCode == code {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memPromotionRepo) ExistingProductIDs(ctx context.Context, ids []uint) ([]uint, error) {
+	var found []uint
+	for _, id := range ids {
+		if id == 1 || id == 2 {
+			found = append(found, id)
+		}
+	}
+	return found, nil
+}
+
+func (r *memPromotionRepo) AttachProducts(ctx context.Context, promotionID uint, productIDs []uint) error {
+	p := r.promos[promotionID-1]
+	for _, id := range productIDs {
+		p.Products = append(p.Products, entity.PromotionProduct{PromotionID: promotionID, ProductID: id})
+	}
+	return nil
+}
+
+func testPromotionRequest(code string) dto.PromotionRequest {
+	now := time.Now()
+	return dto.PromotionRequest{Name: "Payday", Type: entity.PromotionTypePercentage, StartDate: now, EndDate: now.Add(24 * time.Hour),
+		Discount: 10, UsageLimit: 100, VoucherCode: code, Published: true}
+}
+
+func TestCreatePromotion_Validation(t *testing.T) {
+	svc := NewPromotionService(repository.Repository{PromotionRepo: &memPromotionRepo{}}, zap.NewNop())
+	ctx := context.Background()
+
+	res, err := svc.CreatePromotion(ctx, testPromotionRequest(" payday10 "))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.VoucherCode != "PAYDAY10" {
+		t.Fatalf("expected the voucher code upper cased, got %q", res.VoucherCode)
+	}
+	if _, err := svc.CreatePromotion(ctx, testPromotionRequest("PayDay10")); err == nil {
+		t.Fatalf("expected a duplicate voucher code to be rejected")
+	}
+	// keeping its own code is not a duplicate
+	if _, err := svc.UpdatePromotion(ctx, res.ID, testPromotionRequest("PAYDAY10")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := testPromotionRequest("")
+	req.Discount = 150
+	if _, err := svc.CreatePromotion(ctx, req); err == nil {
+		t.Fatalf("expected a percentage over 100 to be rejected")
+	}
+	req = testPromotionRequest("")
+	req.EndDate = req.StartDate.Add(-time.Hour)
+	if _, err := svc.CreatePromotion(ctx, req); err == nil {
+		t.Fatalf("expected an end date before the start to be rejected")
+	}
+}
+
+func TestAttachPromotionProducts(t *testing.T) {
+	repo := &memPromotionRepo{}
+	svc := NewPromotionService(repository.Repository{PromotionRepo: repo}, zap.NewNop())
+	ctx := context.Background()
+	promo, _ := svc.CreatePromotion(ctx, testPromotionRequest(""))
+
+	if _, err := svc.AttachProducts(ctx, promo.ID, dto.PromotionProductsRequest{ProductIDs: []uint{1, 9}}); err == nil || err.Error() != "product 9 not found" {
+		t.Fatalf("expected unknown products to be rejected, got %v", err)
+	}
+	res, err := svc.AttachProducts(ctx, promo.ID, dto.PromotionProductsRequest{ProductIDs: []uint{1, 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Products) != 2 || res.Products[1].ProductID != 2 {
+		t.Fatalf("expected both products attached, got %+v", res.Products)
+	}
+}
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Settlement line types.
+const (
+	settlementPayment = "payment"
+	settlementRefund  = "refund"
+)
+
+type ReconciliationService interface {
+	Reconcile(ctx context.Context, adminID uint, req dto.ReconcileRequest, fileName string, settlement io.Reader) (*dto.ReconciliationRunResponse, error)
+	ListRuns(ctx context.Context, page, limit int) (*dto.ReconciliationListResponse, error)
+	GetRun(ctx context.Context, id uint, issue string) (*dto.ReconciliationRunResponse, error)
+}
+
+type reconciliationService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewReconciliationService(repo repository.Repository, logger *zap.Logger) ReconciliationService {
+	return &reconciliationService{repo: repo, logger: logger}
+}
+
+// settlementLine is one row of a provider settlement file.
+type settlementLine struct {
+	line     int
+	chargeID string
+	kind     string
+	amount   float64
+}
+
+// parseSettlement reads a settlement CSV with a header row holding at least
+// charge_id and amount, and optionally type (payment or refund; payment when
+// empty). Other columns are ignored.
+func parseSettlement(r io.Reader) ([]settlementLine, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("settlement file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := map[string]int{"charge_id": -1, "amount": -1, "type": -1}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, ok := cols[h]; ok {
+			cols[h] = i
+		}
+	}
+	if cols["charge_id"] < 0 || cols["amount"] < 0 {
+		return nil, errors.New("settlement file needs charge_id and amount columns")
+	}
+
+	field := func(rec []string, col int) string {
+		if col < 0 || col >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[col])
+	}
+	var lines []settlementLine
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		n, _ := cr.FieldPos(0)
+		l := settlementLine{line: n, chargeID: field(rec, cols["charge_id"]), kind: strings.ToLower(field(rec, cols["type"]))}
+		if l.chargeID == "" {
+			return nil, fmt.Errorf("line %d: charge_id is empty", n)
+		}
+		if l.kind == "" {
+			l.kind = settlementPayment
+		}
+		if l.kind != settlementPayment && l.kind != settlementRefund {
+			return nil, fmt.Errorf("line %d: type must be payment or refund", n)
+		}
+		if l.amount, err = strconv.ParseFloat(field(rec, cols["amount"]), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount", n)
+		}
+		lines = append(lines, l)
+	}
+}
+
+// Reconcile compares the settlement file of a provider with the payments and
+// refunds recorded for the period, and stores the mismatches as a run.
+// Settlement lines for charges paid outside the period are compared with
+// their payment rather than flagged.
+func (s *reconciliationService) Reconcile(ctx context.Context, adminID uint, req dto.ReconcileRequest, fileName string, settlement io.Reader) (*dto.ReconciliationRunResponse, error) {
+	from, to := req.From, req.To.AddDate(0, 0, 1)
+	if !from.Before(to) {
+		return nil, errors.New("from must not be after to")
+	}
+	lines, err := parseSettlement(settlement)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.PaymentRepo.ListSettledPayments(ctx, req.Method, from, to)
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[string]*entity.Payment, len(payments))
+	for i := range payments {
+		recorded[payments[i].ChargeID] = &payments[i]
+	}
+	lookup := func(chargeID string) *entity.Payment {
+		if p, ok := recorded[chargeID]; ok {
+			return p
+		}
+		p, err := s.repo.PaymentRepo.GetPaymentByChargeID(ctx, chargeID)
+		if err != nil || p.Method != req.Method {
+			p = nil
+		}
+		recorded[chargeID] = p
+		return p
+	}
+
+	run := &entity.ReconciliationRun{
+		Method:    req.Method,
+		FileName:  fileName,
+		From:      from,
+		To:        to,
+		Rows:      len(lines),
+		StartedBy: fmt.Sprintf("admin:%d", adminID),
+	}
+	flag := func(issue, kind, chargeID string, p *entity.Payment, line int, recordedAmount, settledAmount float64) {
+		item := entity.ReconciliationItem{Issue: issue, Type: kind, ChargeID: chargeID, Line: line, RecordedAmount: recordedAmount, SettledAmount: settledAmount}
+		if p != nil {
+			orderID := p.OrderID
+			item.OrderID = &orderID
+		}
+		run.Items = append(run.Items, item)
+	}
+
+	settledPayment := map[string]bool{}
+	refunds := map[string]float64{}
+	refundLine := map[string]int{}
+	var refundOrder []string
+	for _, l := range lines {
+		if l.kind == settlementRefund {
+			// partial refunds settle as separate lines, so they are compared in total
+			if _, ok := refunds[l.chargeID]; !ok {
+				refundOrder = append(refundOrder, l.chargeID)
+				refundLine[l.chargeID] = l.line
+			}
+			refunds[l.chargeID] += l.amount
+			continue
+		}
+		p := lookup(l.chargeID)
+		switch {
+		case settledPayment[l.chargeID]:
+			flag(entity.ReconcileDuplicate, settlementPayment, l.chargeID, p, l.line, 0, l.amount)
+		case p == nil:
+			flag(entity.ReconcileMissingInRecords, settlementPayment, l.chargeID, nil, l.line, 0, l.amount)
+		case roundMoney(p.Amount) != roundMoney(l.amount):
+			flag(entity.ReconcileAmountDiffers, settlementPayment, l.chargeID, p, l.line, p.Amount, l.amount)
+		default:
+			run.Matched++
+		}
+		settledPayment[l.chargeID] = true
+	}
+	for _, chargeID := range refundOrder {
+		p, amount := lookup(chargeID), refunds[chargeID]
+		switch {
+		case p == nil:
+			flag(entity.ReconcileMissingInRecords, settlementRefund, chargeID, nil, refundLine[chargeID], 0, amount)
+		case roundMoney(p.RefundedAmount) != roundMoney(amount):
+			flag(entity.ReconcileAmountDiffers, settlementRefund, chargeID, p, refundLine[chargeID], p.RefundedAmount, amount)
+		default:
+			run.Matched++
+		}
+	}
+	for _, p := range payments {
+		if !settledPayment[p.ChargeID] {
+			flag(entity.ReconcileMissingInSettlement, settlementPayment, p.ChargeID, &p, 0, p.Amount, 0)
+		}
+		if _, ok := refunds[p.ChargeID]; !ok && p.RefundedAmount > 0 {
+			flag(entity.ReconcileMissingInSettlement, settlementRefund, p.ChargeID, &p, 0, p.RefundedAmount, 0)
+		}
+	}
+	run.Flagged = len(run.Items)
+
+	if err := s.repo.ReconcileRepo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+	res := toReconciliationRunResponse(*run, run.Items)
+	return &res, nil
+}
+
+func (s *reconciliationService) ListRuns(ctx context.Context, page, limit int) (*dto.ReconciliationListResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	runs, total, err := s.repo.ReconcileRepo.ListRuns(ctx, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.ReconciliationRunResponse, 0, len(runs))
+	for _, r := range runs {
+		items = append(items, toReconciliationRunResponse(r, nil))
+	}
+	return &dto.ReconciliationListResponse{
+		Items:        items,
+		CurrentPage:  page,
+		Limit:        limit,
+		TotalPages:   int((total + int64(limit) - 1) / int64(limit)),
+		TotalRecords: total,
+	}, nil
+}
+
+// GetRun returns a run with its mismatches, optionally only those of one issue.
+func (s *reconciliationService) GetRun(ctx context.Context, id uint, issue string) (*dto.ReconciliationRunResponse, error) {
+	run, err := s.repo.ReconcileRepo.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ReconcileRepo.ListItems(ctx, run.ID, issue)
+	if err != nil {
+		return nil, err
+	}
+	res := toReconciliationRunResponse(*run, items)
+	if res.Items == nil {
+		res.Items = []dto.ReconciliationItemResponse{}
+	}
+	return &res, nil
+}
+
+func toReconciliationRunResponse(r entity.ReconciliationRun, items []entity.ReconciliationItem) dto.ReconciliationRunResponse {
+	res := dto.ReconciliationRunResponse{
+		ID:        r.ID,
+		Method:    r.Method,
+		FileName:  r.FileName,
+		From:      r.From,
+		To:        r.To,
+		Rows:      r.Rows,
+		Matched:   r.Matched,
+		Flagged:   r.Flagged,
+		StartedBy: r.StartedBy,
+		CreatedAt: r.CreatedAt,
+	}
+	for _, it := range items {
+		res.Items = append(res.Items, dto.ReconciliationItemResponse{
+			Issue:          it.Issue,
+			Type:           it.Type,
+			ChargeID:       it.ChargeID,
+			OrderID:        it.OrderID,
+			Line:           it.Line,
+			RecordedAmount: it.RecordedAmount,
+			SettledAmount:  it.SettledAmount,
+		})
+	}
+	return res
+}
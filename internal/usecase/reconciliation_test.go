@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+// Reconciliation store that keeps the last run
+type memReconcileRepo struct{ run *entity.ReconciliationRun }
+
+func (r *memReconcileRepo) CreateRun(ctx context.Context, run *entity.ReconciliationRun) error {
+	run.ID = 1
+	r.run = run
+	return nil
+}
+
+func (r *memReconcileRepo) ListRuns(ctx context.Context, page, limit int) ([]entity.ReconciliationRun, int64, error) {
+	return []entity.ReconciliationRun{*r.run}, 1, nil
+}
+
+func (r *memReconcileRepo) GetRun(ctx context.Context, id uint) (*entity.ReconciliationRun, error) {
+	return r.run, nil
+}
+
+func (r *memReconcileRepo) ListItems(ctx context.Context, runID uint, issue string) ([]entity.ReconciliationItem, error) {
+	var items []entity.ReconciliationItem
+	for _, it := range r.run.Items {
+		if issue == "" || it.Issue == issue {
+			items = append(items, it)
+		}
+	}
+	return items, nil
+}
+
+func TestReconcile_FlagsMismatches(t *testing.T) {
+	day := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	before := day.AddDate(0, 0, -20)
+	payments := &memPaymentRepo{payments: []entity.Payment{
+		{Model: entity.Model{ID: 1}, OrderID: 11, Method: "gopay", ChargeID: "ch-1", Amount: 100, PaidAt: &day},
+		{Model: entity.Model{ID: 2}, OrderID: 12, Method: "gopay", ChargeID: "ch-2", Amount: 50, RefundedAmount: 20, PaidAt: &day},
+		{Model: entity.Model{ID: 3}, OrderID: 13, Method: "gopay", ChargeID: "ch-3", Amount: 80, PaidAt: &day},
+		{Model: entity.Model{ID: 4}, OrderID: 14, Method: "gopay", ChargeID: "ch-4", Amount: 70, PaidAt: &before},
+	}}
+	runs := &memReconcileRepo{}
+	svc := NewReconciliationService(repository.Repository{PaymentRepo: payments, ReconcileRepo: runs}, zap.NewNop())
+
+	file := "charge_id,type,amount,fee\n" +
+		"ch-1,payment,100,2\n" +
+		"ch-2,payment,50,1\n" +
+		"ch-2,refund,10,0\n" +
+		"ch-1,payment,100,2\n" +
+		"ch-9,payment,30,1\n" +
+		"ch-4,,60,1\n"
+	req := dto.ReconcileRequest{Method: "gopay", From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)}
+	res, err := svc.Reconcile(context.Background(), 1, req, "settlement.csv", strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Rows != 6 || res.Matched != 2 || res.Flagged != 5 {
+		t.Fatalf("expected 6 rows, 2 matched and 5 flagged, got %+v", res)
+	}
+
+	want := map[string]string{
+		entity.ReconcileDuplicate + " payment ch-1":           "line 5",
+		entity.ReconcileMissingInRecords + " payment ch-9":    "line 6",
+		entity.ReconcileAmountDiffers + " payment ch-4":       "line 7",
+		entity.ReconcileAmountDiffers + " refund ch-2":        "line 4",
+		entity.ReconcileMissingInSettlement + " payment ch-3": "line 0",
+	}
+	for _, it := range res.Items {
+		key := it.Issue + " " + it.Type + " " + it.ChargeID
+		line, ok := want[key]
+		if !ok {
+			t.Fatalf("unexpected item %+v", it)
+		}
+		if got := "line " + strconv.Itoa(it.Line); got != line {
+			t.Fatalf("%s: expected %s, got %s", key, line, got)
+		}
+		delete(want, key)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing items: %v", want)
+	}
+
+	only, err := svc.GetRun(context.Background(), res.ID, entity.ReconcileDuplicate)
+	if err != nil || len(only.Items) != 1 || *only.Items[0].OrderID != 11 {
+		t.Fatalf("expected only the duplicate of order 11, got %+v, %v", only, err)
+	}
+}
+
+func TestReconcile_RejectsInvalidSettlement(t *testing.T) {
+	svc := NewReconciliationService(repository.Repository{PaymentRepo: &memPaymentRepo{}, ReconcileRepo: &memReconcileRepo{}}, zap.NewNop())
+	req := dto.ReconcileRequest{Method: "gopay", From: time.Now(), To: time.Now()}
+
+	for file, want := range map[string]string{
+		"":                             "settlement file is empty",
+		"id,total\nch-1,10\n":          "settlement file needs charge_id and amount columns",
+		"charge_id,amount\nch-1,ten\n": "line 2: invalid amount",
+		"charge_id,type,amount\nch-1,chargeback,10\n": "line 2: type must be payment or refund",
+	} {
+		_, err := svc.Reconcile(context.Background(), 1, req, "s.csv", strings.NewReader(file))
+		if err == nil || err.Error() != want {
+			t.Fatalf("expected %q, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// RegionService serves the province and city lists for address forms.
+type RegionService interface {
+	ListProvinces(ctx context.Context) ([]dto.ProvinceResponse, error)
+	ListCities(ctx context.Context, provinceID uint, q dto.CityListQuery) ([]dto.CityResponse, error)
+}
+
+type regionService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewRegionService(repo repository.Repository, logger *zap.Logger) RegionService {
+	return &regionService{repo: repo, logger: logger}
+}
+
+func (s *regionService) ListProvinces(ctx context.Context) ([]dto.ProvinceResponse, error) {
+	provinces, err := s.repo.RegionRepo.ListProvinces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.ProvinceResponse, 0, len(provinces))
+	for _, p := range provinces {
+		res = append(res, dto.ProvinceResponse{ID: p.ID, Name: p.Name})
+	}
+	return res, nil
+}
+
+func (s *regionService) ListCities(ctx context.Context, provinceID uint, q dto.CityListQuery) ([]dto.CityResponse, error) {
+	if _, err := s.repo.RegionRepo.GetProvince(ctx, provinceID); err != nil {
+		return nil, err
+	}
+	cities, err := s.repo.RegionRepo.ListCities(ctx, provinceID, strings.TrimSpace(q.Search))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.CityResponse, 0, len(cities))
+	for _, c := range cities {
+		res = append(res, dto.CityResponse{ID: c.ID, ProvinceID: c.ProvinceID, Name: c.Name})
+	}
+	return res, nil
+}
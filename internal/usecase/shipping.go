@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type ShippingService interface {
+	ListZones(ctx context.Context) ([]dto.ShippingZoneResponse, error)
+	CreateZone(ctx context.Context, req dto.ShippingZoneRequest) (*dto.ShippingZoneResponse, error)
+	UpdateZone(ctx context.Context, id uint, req dto.ShippingZoneRequest) (*dto.ShippingZoneResponse, error)
+	DeleteZone(ctx context.Context, id uint) error
+	CreateMethod(ctx context.Context, zoneID uint, req dto.ShippingMethodRequest) (*dto.ShippingMethodResponse, error)
+	UpdateMethod(ctx context.Context, id uint, req dto.ShippingMethodRequest) (*dto.ShippingMethodResponse, error)
+	DeleteMethod(ctx context.Context, id uint) error
+}
+
+type shippingService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewShippingService(repo repository.Repository, logger *zap.Logger) ShippingService {
+	return &shippingService{repo: repo, logger: logger}
+}
+
+func (s *shippingService) ListZones(ctx context.Context) ([]dto.ShippingZoneResponse, error) {
+	zones, err := s.repo.ShippingRepo.ListZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.ShippingZoneResponse, 0, len(zones))
+	for _, z := range zones {
+		res = append(res, toShippingZoneResponse(z))
+	}
+	return res, nil
+}
+
+func (s *shippingService) CreateZone(ctx context.Context, req dto.ShippingZoneRequest) (*dto.ShippingZoneResponse, error) {
+	z := &entity.ShippingZone{}
+	applyShippingZoneRequest(z, req)
+	if err := s.repo.ShippingRepo.SaveZone(ctx, z); err != nil {
+		return nil, err
+	}
+	res := toShippingZoneResponse(*z)
+	return &res, nil
+}
+
+// UpdateZone renames the zone and replaces its rules; methods are kept.
+func (s *shippingService) UpdateZone(ctx context.Context, id uint, req dto.ShippingZoneRequest) (*dto.ShippingZoneResponse, error) {
+	z, err := s.repo.ShippingRepo.GetZone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyShippingZoneRequest(z, req)
+	if err := s.repo.ShippingRepo.SaveZone(ctx, z); err != nil {
+		return nil, err
+	}
+	res := toShippingZoneResponse(*z)
+	return &res, nil
+}
+
+func (s *shippingService) DeleteZone(ctx context.Context, id uint) error {
+	if _, err := s.repo.ShippingRepo.GetZone(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.ShippingRepo.DeleteZone(ctx, id)
+}
+
+func (s *shippingService) CreateMethod(ctx context.Context, zoneID uint, req dto.ShippingMethodRequest) (*dto.ShippingMethodResponse, error) {
+	if _, err := s.repo.ShippingRepo.GetZone(ctx, zoneID); err != nil {
+		return nil, err
+	}
+	m := &entity.ShippingMethod{ZoneID: zoneID, Active: true}
+	if err := applyShippingMethodRequest(m, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ShippingRepo.SaveMethod(ctx, m); err != nil {
+		return nil, err
+	}
+	res := toShippingMethodResponse(*m)
+	return &res, nil
+}
+
+// UpdateMethod changes the method and replaces its rate tiers.
+func (s *shippingService) UpdateMethod(ctx context.Context, id uint, req dto.ShippingMethodRequest) (*dto.ShippingMethodResponse, error) {
+	m, err := s.repo.ShippingRepo.GetMethod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyShippingMethodRequest(m, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ShippingRepo.SaveMethod(ctx, m); err != nil {
+		return nil, err
+	}
+	res := toShippingMethodResponse(*m)
+	return &res, nil
+}
+
+func (s *shippingService) DeleteMethod(ctx context.Context, id uint) error {
+	if _, err := s.repo.ShippingRepo.GetMethod(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.ShippingRepo.DeleteMethod(ctx, id)
+}
+
+func applyShippingZoneRequest(z *entity.ShippingZone, req dto.ShippingZoneRequest) {
+	z.Name = strings.TrimSpace(req.Name)
+	z.Rules = make([]entity.ShippingZoneRule, 0, len(req.Rules))
+	for _, r := range req.Rules {
+		z.Rules = append(z.Rules, entity.ShippingZoneRule{
+			Province:     strings.TrimSpace(r.Province),
+			PostalPrefix: strings.TrimSpace(r.PostalPrefix),
+		})
+	}
+}
+
+func applyShippingMethodRequest(m *entity.ShippingMethod, req dto.ShippingMethodRequest) error {
+	m.Name = strings.TrimSpace(req.Name)
+	m.EstimatedDays = req.EstimatedDays
+	if req.Active != nil {
+		m.Active = *req.Active
+	}
+	m.Rates = make([]entity.ShippingRate, 0, len(req.Rates))
+	for _, r := range req.Rates {
+		if r.MaxWeight != 0 && r.MaxWeight < r.MinWeight {
+			return errors.New("max_weight must not be below min_weight")
+		}
+		m.Rates = append(m.Rates, entity.ShippingRate{
+			MinWeight:   r.MinWeight,
+			MaxWeight:   r.MaxWeight,
+			MinSubtotal: roundMoney(r.MinSubtotal),
+			Fee:         roundMoney(r.Fee),
+		})
+	}
+	return nil
+}
+
+// shippingOption is a method that can deliver an order, with its fee.
+type shippingOption struct {
+	method entity.ShippingMethod
+	fee    float64
+}
+
+// matchShippingZone returns the zone whose most specific rule matches addr,
+// or nil when no zone covers it. A longer postal prefix is more specific
+// than a province; ties go to the oldest zone.
+func matchShippingZone(zones []entity.ShippingZone, addr entity.Address) *entity.ShippingZone {
+	var best *entity.ShippingZone
+	bestScore := -1
+	for i := range zones {
+		for _, r := range zones[i].Rules {
+			if score := ruleSpecificity(r, addr); score > bestScore {
+				best, bestScore = &zones[i], score
+			}
+		}
+	}
+	return best
+}
+
+// ruleSpecificity scores how closely r matches addr, or -1 if it does not.
+func ruleSpecificity(r entity.ShippingZoneRule, addr entity.Address) int {
+	if r.Province != "" && !strings.EqualFold(r.Province, strings.TrimSpace(addr.Province)) {
+		return -1
+	}
+	if r.PostalPrefix != "" && !strings.HasPrefix(strings.TrimSpace(addr.PostalCode), r.PostalPrefix) {
+		return -1
+	}
+	score := 2 * len(r.PostalPrefix)
+	if r.Province != "" {
+		score++
+	}
+	return score
+}
+
+// shippingOptions rates the active methods of zone for an order of weight
+// grams worth subtotal, cheapest first. Methods without a matching tier
+// cannot deliver the order and are left out.
+func shippingOptions(zone entity.ShippingZone, weight int, subtotal float64) []shippingOption {
+	var options []shippingOption
+	for _, m := range zone.Methods {
+		if !m.Active {
+			continue
+		}
+		if fee, ok := shippingFee(m, weight, subtotal); ok {
+			options = append(options, shippingOption{method: m, fee: fee})
+		}
+	}
+	sort.SliceStable(options, func(i, j int) bool { return options[i].fee < options[j].fee })
+	return options
+}
+
+// shippingFee picks the tier of m covering weight. When several do, the one
+// with the highest min_subtotal reached wins, so a free shipping tier above
+// an order value overrides the regular one.
+func shippingFee(m entity.ShippingMethod, weight int, subtotal float64) (float64, bool) {
+	var best *entity.ShippingRate
+	for i, r := range m.Rates {
+		if weight < r.MinWeight || (r.MaxWeight > 0 && weight > r.MaxWeight) || subtotal < r.MinSubtotal {
+			continue
+		}
+		if best == nil || r.MinSubtotal > best.MinSubtotal || (r.MinSubtotal == best.MinSubtotal && r.Fee < best.Fee) {
+			best = &m.Rates[i]
+		}
+	}
+	if best == nil {
+		return 0, false
+	}
+	return roundMoney(best.Fee), true
+}
+
+func toShippingZoneResponse(z entity.ShippingZone) dto.ShippingZoneResponse {
+	res := dto.ShippingZoneResponse{
+		ID:      z.ID,
+		Name:    z.Name,
+		Rules:   make([]dto.ShippingZoneRuleResponse, 0, len(z.Rules)),
+		Methods: make([]dto.ShippingMethodResponse, 0, len(z.Methods)),
+	}
+	for _, r := range z.Rules {
+		res.Rules = append(res.Rules, dto.ShippingZoneRuleResponse{Province: r.Province, PostalPrefix: r.PostalPrefix})
+	}
+	for _, m := range z.Methods {
+		res.Methods = append(res.Methods, toShippingMethodResponse(m))
+	}
+	return res
+}
+
+func toShippingMethodResponse(m entity.ShippingMethod) dto.ShippingMethodResponse {
+	res := dto.ShippingMethodResponse{
+		ID:            m.ID,
+		ZoneID:        m.ZoneID,
+		Name:          m.Name,
+		EstimatedDays: m.EstimatedDays,
+		Active:        m.Active,
+		Rates:         make([]dto.ShippingRateResponse, 0, len(m.Rates)),
+	}
+	for _, r := range m.Rates {
+		res.Rates = append(res.Rates, dto.ShippingRateResponse{
+			MinWeight:   r.MinWeight,
+			MaxWeight:   r.MaxWeight,
+			MinSubtotal: r.MinSubtotal,
+			Fee:         r.Fee,
+		})
+	}
+	return res
+}
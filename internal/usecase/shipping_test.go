@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+)
+
+// Shipping setup held in memory; no zones means free shipping
+type memShippingRepo struct {
+	zones []entity.ShippingZone
+}
+
+func (r *memShippingRepo) ListZones(ctx context.Context) ([]entity.ShippingZone, error) {
+	return r.zones, nil
+}
+
+func (r *memShippingRepo) GetZone(ctx context.Context, id uint) (*entity.ShippingZone, error) {
+	for _, z := range r.zones {
+		if z.ID == id {
+			return &z, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memShippingRepo) SaveZone(ctx context.Context, z *entity.ShippingZone) error {
+	if z.ID == 0 {
+		z.ID = uint(len(r.zones) + 1)
+	}
+	r.zones = append(r.zones, *z)
+	return nil
+}
+
+func (r *memShippingRepo) DeleteZone(ctx context.Context, id uint) error { return nil }
+
+func (r *memShippingRepo) GetMethod(ctx context.Context, id uint) (*entity.ShippingMethod, error) {
+	return nil, errors.New("record not found")
+}
+
+func (r *memShippingRepo) SaveMethod(ctx context.Context, m *entity.ShippingMethod) error { return nil }
+func (r *memShippingRepo) DeleteMethod(ctx context.Context, id uint) error                { return nil }
+
+// Address repo returning a fixed address
+type fixedAddressRepo struct {
+	mockAddressRepo
+	addr entity.Address
+}
+
+func (r *fixedAddressRepo) GetAddressByID(ctx context.Context, id uint) (*entity.Address, error) {
+	a := r.addr
+	return &a, nil
+}
+
+func testShippingZones() []entity.ShippingZone {
+	regular := entity.ShippingMethod{Model: entity.Model{ID: 1}, Name: "Regular", Active: true, Rates: []entity.ShippingRate{
+		{MinWeight: 0, MaxWeight: 1000, Fee: 10},
+		{MinWeight: 1001, MaxWeight: 5000, Fee: 20},
+		{MinWeight: 0, MaxWeight: 5000, MinSubtotal: 500, Fee: 0},
+	}}
+	express := entity.ShippingMethod{Model: entity.Model{ID: 2}, Name: "Express", Active: true, Rates: []entity.ShippingRate{
+		{MinWeight: 0, MaxWeight: 2000, Fee: 35},
+	}}
+	return []entity.ShippingZone{
+		{Model: entity.Model{ID: 1}, Name: "Java", Rules: []entity.ShippingZoneRule{{Province: "Jawa Barat"}, {Province: "DKI Jakarta"}},
+			Methods: []entity.ShippingMethod{regular, express}},
+		{Model: entity.Model{ID: 2}, Name: "Bandung city", Rules: []entity.ShippingZoneRule{{PostalPrefix: "401"}},
+			Methods: []entity.ShippingMethod{{Model: entity.Model{ID: 3}, Name: "Same day", Active: true, Rates: []entity.ShippingRate{{Fee: 5}}}}},
+	}
+}
+
+func shippingCheckoutService(weight, qty int, price float64, addr entity.Address) *orderService {
+	variant := availableVariant(1)
+	variant.Weight = weight
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: variant, Quantity: qty, UnitPrice: price}}}
+	repoVal := repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{},
+		AddressRepo: &fixedAddressRepo{addr: addr}, ShippingRepo: &memShippingRepo{zones: testShippingZones()}}
+	return &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+}
+
+func TestQuoteOrder_ShippingOptionsByWeightAndZone(t *testing.T) {
+	jakarta := entity.Address{CustomerID: 1, Province: "DKI Jakarta", PostalCode: "10110"}
+	svc := shippingCheckoutService(800, 2, 100, jakarta)
+
+	q, err := svc.QuoteOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !q.Valid || q.TotalWeight != 1600 || len(q.ShippingOptions) != 2 || q.ShippingOptions[0].Name != "Regular" || q.ShippingOptions[0].Fee != 20 {
+		t.Fatalf("expected regular at 20 then express, got %+v", q)
+	}
+	if q.ShippingFee != 20 || q.GrandTotal != 220 || *q.ShippingMethodID != 1 {
+		t.Fatalf("expected the cheapest option to be charged, got %+v", q)
+	}
+
+	express := uint(2)
+	q, _ = svc.QuoteOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", ShippingMethodID: &express}, 1)
+	if q.ShippingFee != 35 || q.GrandTotal != 235 {
+		t.Fatalf("expected express to be charged, got %+v", q)
+	}
+
+	// the free tier applies from a subtotal of 500
+	svc = shippingCheckoutService(800, 5, 100, jakarta)
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.ShippingFee != 0 || res.ShippingMethod != "Regular" || res.TotalWeight != 4000 {
+		t.Fatalf("expected free regular shipping, got %+v", res)
+	}
+}
+
+func TestQuoteOrder_MostSpecificZoneWins(t *testing.T) {
+	svc := shippingCheckoutService(500, 1, 100, entity.Address{CustomerID: 1, Province: "jawa barat", PostalCode: "40115"})
+	q, err := svc.QuoteOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.ShippingOptions) != 1 || q.ShippingOptions[0].Name != "Same day" || q.ShippingFee != 5 {
+		t.Fatalf("expected the postal code zone over the province zone, got %+v", q)
+	}
+}
+
+func TestCreateOrder_RejectsUndeliverableOrders(t *testing.T) {
+	ctx := context.Background()
+	req := dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay"}
+
+	svc := shippingCheckoutService(500, 1, 100, entity.Address{CustomerID: 1, Province: "Papua", PostalCode: "99111"})
+	if _, err := svc.CreateOrder(ctx, req, 1); err == nil || err.Error() != "we do not ship to this address" {
+		t.Fatalf("expected an uncovered address to be rejected, got %v", err)
+	}
+
+	jakarta := entity.Address{CustomerID: 1, Province: "DKI Jakarta"}
+	svc = shippingCheckoutService(3000, 2, 100, jakarta)
+	if _, err := svc.CreateOrder(ctx, req, 1); err == nil || err.Error() != "no shipping method can deliver this order" {
+		t.Fatalf("expected an order over every weight tier to be rejected, got %v", err)
+	}
+
+	sameDay := uint(3)
+	req.ShippingMethodID = &sameDay
+	svc = shippingCheckoutService(500, 1, 100, jakarta)
+	if _, err := svc.CreateOrder(ctx, req, 1); err == nil || err.Error() != "shipping method is not available for this address" {
+		t.Fatalf("expected a method of another zone to be rejected, got %v", err)
+	}
+}
@@ -17,6 +17,7 @@ type StockService interface {
 	Add(ctx context.Context, req dto.AddStockRequest) error
 	Set(ctx context.Context, req dto.SetStockRequest) error
 	Delete(ctx context.Context, req dto.DeleteStockRequest) error
+	SetWeight(ctx context.Context, req dto.SetWeightRequest) error
 	VariantsDropdown(ctx context.Context, q dto.VariantDropdownQuery) (*dto.StockListResponse, error)
 }
 
@@ -85,6 +86,10 @@ func (s *stockService) Delete(ctx context.Context, req dto.DeleteStockRequest) e
 	return s.Repo.StockRepo.DeleteStock(ctx, req.VariantID)
 }
 
+func (s *stockService) SetWeight(ctx context.Context, req dto.SetWeightRequest) error {
+	return s.Repo.StockRepo.SetWeight(ctx, req.VariantID, req.Weight)
+}
+
 func (s *stockService) VariantsDropdown(ctx context.Context, q dto.VariantDropdownQuery) (*dto.StockListResponse, error) {
 	if q.Page <= 0 {
 		q.Page = 1
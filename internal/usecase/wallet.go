@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+
+	"go.uber.org/zap"
+)
+
+type WalletService interface {
+	GetWallet(ctx context.Context, customerID uint, page, limit int) (*dto.WalletResponse, error)
+	Adjust(ctx context.Context, customerID uint, adminID uint, req dto.WalletAdjustRequest) (*dto.WalletEntryResponse, error)
+}
+
+type walletService struct {
+	repo   repository.Repository
+	logger *zap.Logger
+}
+
+func NewWalletService(repo repository.Repository, logger *zap.Logger) WalletService {
+	return &walletService{repo: repo, logger: logger}
+}
+
+// GetWallet returns the customer's balance with their ledger, newest first.
+func (s *walletService) GetWallet(ctx context.Context, customerID uint, page, limit int) (*dto.WalletResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	balance, err := s.repo.WalletRepo.GetBalance(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	entries, total, err := s.repo.WalletRepo.ListEntries(ctx, customerID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.WalletResponse{
+		CustomerID:   customerID,
+		Balance:      balance,
+		Entries:      make([]dto.WalletEntryResponse, 0, len(entries)),
+		CurrentPage:  page,
+		Limit:        limit,
+		TotalRecords: total,
+	}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, toWalletEntryResponse(e))
+	}
+	return res, nil
+}
+
+// Adjust lets an admin credit a customer, e.g. as compensation, or debit a
+// correction. A referenced order must belong to the customer.
+func (s *walletService) Adjust(ctx context.Context, customerID uint, adminID uint, req dto.WalletAdjustRequest) (*dto.WalletEntryResponse, error) {
+	if req.OrderID != nil {
+		o, err := s.repo.OrderRepo.GetOrderByID(ctx, *req.OrderID)
+		if err != nil {
+			return nil, errors.New("order not found")
+		}
+		if o.CustomerID != customerID {
+			return nil, errors.New("order does not belong to this customer")
+		}
+	}
+	e := &entity.WalletEntry{
+		CustomerID: customerID,
+		Amount:     roundMoney(req.Amount),
+		Reason:     req.Reason,
+		OrderID:    req.OrderID,
+		CreatedBy:  fmt.Sprintf("admin:%d", adminID),
+	}
+	var err error
+	if req.Type == entity.WalletEntryDebit {
+		err = s.repo.WalletRepo.Debit(ctx, e)
+	} else {
+		err = s.repo.WalletRepo.Credit(ctx, e)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := toWalletEntryResponse(*e)
+	return &res, nil
+}
+
+func toWalletEntryResponse(e entity.WalletEntry) dto.WalletEntryResponse {
+	return dto.WalletEntryResponse{
+		ID:           e.ID,
+		Type:         e.Type,
+		Amount:       e.Amount,
+		BalanceAfter: e.BalanceAfter,
+		Reason:       e.Reason,
+		OrderID:      e.OrderID,
+		PaymentID:    e.PaymentID,
+		CreatedAt:    e.CreatedAt,
+	}
+}
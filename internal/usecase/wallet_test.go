@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"project-app-ecommerce-golang-tim-1/internal/data/entity"
+	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/dto"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+)
+
+// In-memory wallet of a single customer
+type memWalletRepo struct {
+	balance float64
+	entries []entity.WalletEntry
+}
+
+func (r *memWalletRepo) GetBalance(ctx context.Context, customerID uint) (float64, error) {
+	return r.balance, nil
+}
+
+func (r *memWalletRepo) ListEntries(ctx context.Context, customerID uint, limit, offset int) ([]entity.WalletEntry, int64, error) {
+	return r.entries, int64(len(r.entries)), nil
+}
+
+func (r *memWalletRepo) Credit(ctx context.Context, e *entity.WalletEntry) error {
+	r.balance += e.Amount
+	e.Type, e.BalanceAfter = entity.WalletEntryCredit, r.balance
+	r.entries = append(r.entries, *e)
+	return nil
+}
+
+func (r *memWalletRepo) Debit(ctx context.Context, e *entity.WalletEntry) error {
+	if e.Amount > r.balance {
+		return errors.New("insufficient wallet balance")
+	}
+	r.balance -= e.Amount
+	e.Type, e.BalanceAfter = entity.WalletEntryDebit, r.balance
+	r.entries = append(r.entries, *e)
+	return nil
+}
+
+// Order repo whose insert fails, e.g. because the wallet was spent concurrently
+type failingCreateOrderRepo struct{ simpleOrderRepo }
+
+func (r *failingCreateOrderRepo) CreateOrder(ctx context.Context, order *entity.Order) error {
+	return errors.New("insufficient wallet balance")
+}
+
+// Promo repo counting the uses taken and given back
+type countingPromoRepo struct {
+	simplePromoRepo
+	used int
+}
+
+func (r *countingPromoRepo) DecrementUsage(ctx context.Context, id uint) error { r.used++; return nil }
+func (r *countingPromoRepo) RestoreUsage(ctx context.Context, id uint) error   { r.used--; return nil }
+
+func walletCheckoutRepo(balance float64) repository.Repository {
+	cart := &entity.Cart{CustomerID: 1, Items: []entity.CartItem{{ProductVariantID: 1, ProductVariant: availableVariant(1), Quantity: 1, UnitPrice: 100}}}
+	return repository.Repository{CartRepo: &simpleCartRepo{cart: cart}, PromotionRepo: &simplePromoRepo{}, OrderRepo: &simpleOrderRepo{},
+		AddressRepo: &mockAddressRepo{}, ShippingRepo: &memShippingRepo{}, WalletRepo: &memWalletRepo{balance: balance}}
+}
+
+func TestCreateOrder_PaidInFullFromWallet(t *testing.T) {
+	svc := &orderService{repo: walletCheckoutRepo(250), logger: zap.NewNop(), payments: testPayments()}
+	amount := 500.0
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", WalletAmount: &amount}, 1)
+	if err == nil {
+		t.Fatalf("expected more than the balance to be rejected")
+	}
+
+	amount = 100
+	res, err = svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", WalletAmount: &amount}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusPaid || res.PaymentMethod != payment.MethodWallet || res.AmountDue != 0 || res.PaymentExpiresAt != nil {
+		t.Fatalf("expected an order paid from the wallet, got %+v", res)
+	}
+}
+
+func TestCreateOrder_PartialWalletLeavesAmountDue(t *testing.T) {
+	svc := &orderService{repo: walletCheckoutRepo(250), logger: zap.NewNop(), payments: testPayments()}
+	amount := 30.0
+	res, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", WalletAmount: &amount}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != entity.OrderStatusCreated || res.WalletAmount != 30 || res.AmountDue != 70 || res.PaymentMethod != "gopay" {
+		t.Fatalf("expected 70 left to pay with gopay, got %+v", res)
+	}
+}
+
+func TestCreateOrder_RestoresVoucherWhenInsertFails(t *testing.T) {
+	promos := &countingPromoRepo{simplePromoRepo: simplePromoRepo{promo: &entity.Promotion{Model: entity.Model{ID: 3}, Type: "fixed", Discount: 10,
+		StartDate: time.Now().Add(-time.Hour), EndDate: time.Now().Add(time.Hour), Published: true, UsageLimit: 5}}}
+	repoVal := walletCheckoutRepo(250)
+	repoVal.PromotionRepo = promos
+	repoVal.OrderRepo = &failingCreateOrderRepo{}
+	svc := &orderService{repo: repoVal, logger: zap.NewNop(), payments: testPayments()}
+
+	code, amount := "PROMO", 50.0
+	_, err := svc.CreateOrder(context.Background(), dto.CreateOrderRequest{AddressID: 1, PaymentMethod: "gopay", VoucherCode: &code, WalletAmount: &amount}, 1)
+	if err == nil {
+		t.Fatalf("expected the failed insert to be returned")
+	}
+	if promos.used != 0 {
+		t.Fatalf("expected the voucher use to be given back, %d still taken", promos.used)
+	}
+}
+
+func TestPayOrder_ChargesWhatWalletDidNotCover(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 1, PaymentMethod: "gopay", GrandTotal: 150, WalletAmount: 50, Status: entity.OrderStatusCreated}
+	svc, mock, _ := paymentTestService(order)
+	ctx := context.Background()
+
+	started, err := svc.PayOrder(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if started.Amount != 100 {
+		t.Fatalf("expected a charge of 100, got %v", started.Amount)
+	}
+	if _, err := mock.Complete(started.ChargeID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetPayment(ctx, 1, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != entity.OrderStatusPaid {
+		t.Fatalf("expected order to be paid, got %s", order.Status)
+	}
+}
+
+func TestRefundPayment_ToWallet(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 1}, CustomerID: 7, PaymentMethod: payment.MethodCOD, GrandTotal: 120, Status: entity.OrderStatusDelivered}
+	svc, _, paymentRepo := paymentTestService(order)
+	ctx := context.Background()
+	if _, err := svc.CollectCOD(ctx, 1, dto.CollectCODRequest{Amount: 120, CollectedBy: "courier"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	part := 20.0
+	res, err := svc.RefundPayment(ctx, 1, dto.RefundPaymentRequest{Amount: &part, ToWallet: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != string(payment.StatusPartiallyRefunded) || res.RefundedAmount != 20 {
+		t.Fatalf("expected a partial refund, got %+v", res)
+	}
+	if _, err := svc.RefundPayment(ctx, 1, dto.RefundPaymentRequest{Amount: &order.GrandTotal, ToWallet: true}); err == nil {
+		t.Fatalf("expected refunding more than is left to be rejected")
+	}
+	res, err = svc.RefundPayment(ctx, 1, dto.RefundPaymentRequest{ToWallet: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != string(payment.StatusRefunded) || len(paymentRepo.wallet) != 2 || paymentRepo.wallet[1].Amount != 100 || paymentRepo.wallet[1].CustomerID != 7 {
+		t.Fatalf("expected the rest credited to the wallet, got %+v %+v", res, paymentRepo.wallet)
+	}
+}
+
+func TestWalletAdjust_RejectsOtherCustomersOrder(t *testing.T) {
+	order := &entity.Order{Model: entity.Model{ID: 4}, CustomerID: 2}
+	wallet := &memWalletRepo{}
+	svc := NewWalletService(repository.Repository{OrderRepo: &fixedOrderRepo{order: order}, WalletRepo: wallet}, zap.NewNop())
+	ctx := context.Background()
+
+	if _, err := svc.Adjust(ctx, 1, 9, dto.WalletAdjustRequest{Type: entity.WalletEntryCredit, Amount: 10, Reason: "late delivery", OrderID: &order.ID}); err == nil {
+		t.Fatalf("expected an order of another customer to be rejected")
+	}
+	if _, err := svc.Adjust(ctx, 2, 9, dto.WalletAdjustRequest{Type: entity.WalletEntryCredit, Amount: 10, Reason: "late delivery", OrderID: &order.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Adjust(ctx, 2, 9, dto.WalletAdjustRequest{Type: entity.WalletEntryDebit, Amount: 15, Reason: "correction"}); err == nil {
+		t.Fatalf("expected a debit over the balance to be rejected")
+	}
+	res, err := svc.GetWallet(ctx, 2, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Balance != 10 || len(res.Entries) != 1 || res.Entries[0].Reason != "late delivery" {
+		t.Fatalf("unexpected wallet: %+v", res)
+	}
+}
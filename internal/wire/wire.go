@@ -5,22 +5,33 @@ import (
 	"project-app-ecommerce-golang-tim-1/internal/data/repository"
 	"project-app-ecommerce-golang-tim-1/internal/usecase"
 	"project-app-ecommerce-golang-tim-1/pkg/middleware"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
 	"project-app-ecommerce-golang-tim-1/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func Wiring(repo repository.Repository, mLogger middleware.LoggerMiddleware, middlwareAuth middleware.AuthMiddleware, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender) *gin.Engine {
+func Wiring(repo repository.Repository, mLogger middleware.LoggerMiddleware, middlwareAuth middleware.AuthMiddleware, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender, payments *payment.Registry, carriers *shipping.Registry) *gin.Engine {
 	router := gin.New()
 	router.Use(mLogger.LoggingMiddleware())
 	api := router.Group("/api/v1")
 	wireUser(api, middlwareAuth, repo, logger, config, emailSender)
 	wireAuth(api, middlwareAuth, repo, logger, config)
-	wireCustomer(api, middlwareAuth, repo, logger, config)
+	wireCustomer(api, middlwareAuth, repo, logger, config, emailSender, payments, carriers)
 	wireStock(api, middlwareAuth, repo, logger, config)
 	wireCategory(api, middlwareAuth, repo, logger, config)
 	wireBanner(api, middlwareAuth, repo, logger, config)
+	wireOrderAdmin(api, middlwareAuth, repo, logger, config, emailSender, payments, carriers)
+	wirePayment(api, repo, logger, config, emailSender, payments, carriers)
+	wireWallet(api, middlwareAuth, repo, logger)
+	wireGiftCard(api, middlwareAuth, repo, logger)
+	wireReconciliation(api, middlwareAuth, repo, logger)
+	wireShipping(api, middlwareAuth, repo, logger)
+	wireRegion(api, repo, logger)
+	wirePickup(api, middlwareAuth, repo, logger)
+	wirePromotion(api, middlwareAuth, repo, logger)
 	return router
 }
 
@@ -49,7 +60,7 @@ func wireAuth(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware,
 	router.POST("/auth/logout", middlwareAuth.Auth(), adaptorAuth.Logout)
 }
 
-func wireCustomer(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger, config utils.Configuration) {
+func wireCustomer(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender, payments *payment.Registry, carriers *shipping.Registry) {
 	usecaseCustomer := usecase.NewCustomerService(repo, logger, config)
 	adaptorCustomer := adaptor.NewHandlerCustomer(usecaseCustomer, logger)
 	router.POST("/register", adaptorCustomer.RegisterCustomer)
@@ -64,12 +75,70 @@ func wireCustomer(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddlewa
 	customerGroup.DELETE("/address/:id", adaptorAddress.Delete)
 	customerGroup.PATCH("/address/:id/default", adaptorAddress.SetDefault)
 	// Order routes
-	usecaseOrder := usecase.NewOrderService(repo, logger)
+	usecaseOrder := usecase.NewOrderService(repo, logger, config, emailSender, payments, carriers)
 	adaptorOrder := adaptor.NewHandlerOrder(usecaseOrder, logger)
 	customerGroup.POST("/order", middlwareAuth.Auth(), adaptorOrder.CreateOrder)
+	customerGroup.POST("/checkout/quote", adaptorOrder.Quote)
 	customerGroup.GET("/cart", middlwareAuth.Auth(), adaptorOrder.Cart)
 	customerGroup.GET("/order/:id", middlwareAuth.Auth(), adaptorOrder.GetOrderDetail)
 	customerGroup.GET("/order/history", middlwareAuth.Auth(), adaptorOrder.ListOrderHistory)
+	customerGroup.POST("/order/:id/reorder", adaptorOrder.Reorder)
+	customerGroup.POST("/order/:id/pay", adaptorOrder.Pay)
+	customerGroup.GET("/order/:id/payment", adaptorOrder.Payment)
+	// Order message thread
+	usecaseMessage := usecase.NewOrderMessageService(repo, logger, config, emailSender)
+	adaptorMessage := adaptor.NewHandlerOrderMessage(usecaseMessage, logger)
+	customerGroup.GET("/order/:id/messages", adaptorMessage.CustomerList)
+	customerGroup.POST("/order/:id/messages", adaptorMessage.CustomerPost)
+	customerGroup.POST("/order/:id/messages/read", adaptorMessage.CustomerMarkRead)
+}
+
+func wireOrderAdmin(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender, payments *payment.Registry, carriers *shipping.Registry) {
+	usecaseOrder := usecase.NewOrderService(repo, logger, config, emailSender, payments, carriers)
+	adaptorOrder := adaptor.NewHandlerOrder(usecaseOrder, logger)
+	adminGroup := router.Group("/admin/orders")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("", adaptorOrder.AdminList)
+	adminGroup.GET("/export", adaptorOrder.Export)
+	adminGroup.GET("/pick-list", adaptorOrder.PickList)
+	adminGroup.GET("/packing-slips", adaptorOrder.PackingSlips)
+	adminGroup.PATCH("/:id/status", adaptorOrder.UpdateStatus)
+	adminGroup.POST("/:id/shipments", adaptorOrder.CreateShipment)
+	adminGroup.POST("/:id/refund", adaptorOrder.Refund)
+	adminGroup.POST("/:id/cod/collect", adaptorOrder.CollectCOD)
+	adminGroup.POST("/pickup/verify", adaptorOrder.VerifyPickup)
+	adminGroup.PATCH("/:id/shipments/:shipment_id/status", adaptorOrder.UpdateShipmentStatus)
+
+	usecaseMessage := usecase.NewOrderMessageService(repo, logger, config, emailSender)
+	adaptorMessage := adaptor.NewHandlerOrderMessage(usecaseMessage, logger)
+	adminGroup.GET("/:id/messages", adaptorMessage.AdminList)
+	adminGroup.POST("/:id/messages", adaptorMessage.AdminPost)
+	adminGroup.POST("/:id/messages/read", adaptorMessage.AdminMarkRead)
+}
+
+// wirePayment registers the public payment webhook, and the payment page of
+// the mock gateway when PAYMENT_MOCK_ENABLED is set for local development.
+// The mock routes settle charges without authentication.
+func wirePayment(router *gin.RouterGroup, repo repository.Repository, logger *zap.Logger, config utils.Configuration, emailSender utils.EmailSender, payments *payment.Registry, carriers *shipping.Registry) {
+	usecaseOrder := usecase.NewOrderService(repo, logger, config, emailSender, payments, carriers)
+	adaptorOrder := adaptor.NewHandlerOrder(usecaseOrder, logger)
+	router.POST("/payments/webhook/:method", adaptorOrder.PaymentWebhook)
+
+	if !config.PaymentMockEnabled {
+		return
+	}
+	provider, err := payments.Get("mock")
+	if err != nil {
+		return
+	}
+	mock, ok := provider.(*payment.MockProvider)
+	if !ok {
+		return
+	}
+	adaptorMock := adaptor.NewHandlerMockPayment(mock, usecaseOrder, logger)
+	router.GET("/payments/mock/charges/:id", adaptorMock.Get)
+	router.POST("/payments/mock/charges/:id/complete", adaptorMock.Complete)
+	router.POST("/payments/mock/charges/:id/fail", adaptorMock.Fail)
 }
 
 func wireStock(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger, config utils.Configuration) {
@@ -79,6 +148,7 @@ func wireStock(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware,
 	router.GET("/admin/stock/:variant_id", adaptorStock.Detail)
 	router.POST("/admin/stock/add", adaptorStock.Add)
 	router.PUT("/admin/stock/set", adaptorStock.Set)
+	router.PUT("/admin/stock/weight", middlwareAuth.Auth(), middleware.AdminOnly(), adaptorStock.SetWeight)
 	router.DELETE("/admin/stock", adaptorStock.Delete)
 	router.GET("/admin/stock/variants", adaptorStock.VariantsDropdown)
 }
@@ -104,3 +174,88 @@ func wireBanner(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware
 	router.PATCH("/admin/banners/publish", adaptorBanner.TogglePublished)
 	router.DELETE("/admin/banners/:id", adaptorBanner.Delete)
 }
+
+func wireWallet(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger) {
+	usecaseWallet := usecase.NewWalletService(repo, logger)
+	adaptorWallet := adaptor.NewHandlerWallet(usecaseWallet, logger)
+	router.GET("/customer/wallet", middlwareAuth.Auth(), adaptorWallet.Balance)
+
+	adminGroup := router.Group("/admin/customers")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("/:id/wallet", adaptorWallet.AdminBalance)
+	adminGroup.POST("/:id/wallet", adaptorWallet.Adjust)
+}
+
+func wireGiftCard(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger) {
+	usecaseGiftCard := usecase.NewGiftCardService(repo, logger)
+	adaptorGiftCard := adaptor.NewHandlerGiftCard(usecaseGiftCard, logger)
+	router.GET("/customer/gift-cards/:code", middlwareAuth.Auth(), adaptorGiftCard.Balance)
+
+	adminGroup := router.Group("/admin/gift-cards")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("", adaptorGiftCard.List)
+	adminGroup.POST("", adaptorGiftCard.Issue)
+	adminGroup.POST("/bulk", adaptorGiftCard.Generate)
+	adminGroup.GET("/:id", adaptorGiftCard.Get)
+}
+
+func wireReconciliation(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger) {
+	usecaseReconciliation := usecase.NewReconciliationService(repo, logger)
+	adaptorReconciliation := adaptor.NewHandlerReconciliation(usecaseReconciliation, logger)
+	adminGroup := router.Group("/admin/reconciliations")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("", adaptorReconciliation.List)
+	adminGroup.POST("", adaptorReconciliation.Import)
+	adminGroup.GET("/:id", adaptorReconciliation.Get)
+}
+
+func wireShipping(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger) {
+	usecaseShipping := usecase.NewShippingService(repo, logger)
+	adaptorShipping := adaptor.NewHandlerShipping(usecaseShipping, logger)
+	adminGroup := router.Group("/admin/shipping")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("/zones", adaptorShipping.ListZones)
+	adminGroup.POST("/zones", adaptorShipping.CreateZone)
+	adminGroup.PUT("/zones/:id", adaptorShipping.UpdateZone)
+	adminGroup.DELETE("/zones/:id", adaptorShipping.DeleteZone)
+	adminGroup.POST("/zones/:id/methods", adaptorShipping.CreateMethod)
+	adminGroup.PUT("/methods/:id", adaptorShipping.UpdateMethod)
+	adminGroup.DELETE("/methods/:id", adaptorShipping.DeleteMethod)
+}
+
+func wireRegion(router *gin.RouterGroup, repo repository.Repository, logger *zap.Logger) {
+	usecaseRegion := usecase.NewRegionService(repo, logger)
+	adaptorRegion := adaptor.NewHandlerRegion(usecaseRegion, logger)
+	router.GET("/regions/provinces", adaptorRegion.Provinces)
+	router.GET("/regions/provinces/:id/cities", adaptorRegion.Cities)
+}
+
+func wirePickup(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger) {
+	usecasePickup := usecase.NewPickupService(repo, logger)
+	adaptorPickup := adaptor.NewHandlerPickup(usecasePickup, logger)
+	router.GET("/pickup-locations", adaptorPickup.Locations)
+
+	adminGroup := router.Group("/admin/pickup-locations")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("", adaptorPickup.List)
+	adminGroup.POST("", adaptorPickup.Create)
+	adminGroup.PUT("/:id", adaptorPickup.Update)
+	adminGroup.DELETE("/:id", adaptorPickup.Delete)
+}
+
+func wirePromotion(router *gin.RouterGroup, middlwareAuth middleware.AuthMiddleware, repo repository.Repository, logger *zap.Logger) {
+	usecasePromotion := usecase.NewPromotionService(repo, logger)
+	adaptorPromotion := adaptor.NewHandlerPromotion(usecasePromotion, logger)
+	adminGroup := router.Group("/admin/promotions")
+	adminGroup.Use(middlwareAuth.Auth(), middleware.AdminOnly())
+	adminGroup.GET("", adaptorPromotion.List)
+	adminGroup.POST("", adaptorPromotion.Create)
+	adminGroup.GET("/:id", adaptorPromotion.Get)
+	adminGroup.PUT("/:id", adaptorPromotion.Update)
+	adminGroup.DELETE("/:id", adaptorPromotion.Delete)
+	adminGroup.PATCH("/:id/publish", adaptorPromotion.TogglePublished)
+	adminGroup.POST("/:id/products", adaptorPromotion.AttachProducts)
+	adminGroup.DELETE("/:id/products/:product_id", adaptorPromotion.DetachProduct)
+	adminGroup.POST("/:id/categories", adaptorPromotion.AttachCategories)
+	adminGroup.DELETE("/:id/categories/:category_id", adaptorPromotion.DetachCategory)
+}
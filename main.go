@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"project-app-ecommerce-golang-tim-1/cmd"
 	"project-app-ecommerce-golang-tim-1/internal/data"
 	"project-app-ecommerce-golang-tim-1/internal/data/repository"
+	"project-app-ecommerce-golang-tim-1/internal/usecase"
 	"project-app-ecommerce-golang-tim-1/internal/wire"
 	"project-app-ecommerce-golang-tim-1/pkg/database"
 	"project-app-ecommerce-golang-tim-1/pkg/middleware"
+	"project-app-ecommerce-golang-tim-1/pkg/payment"
+	"project-app-ecommerce-golang-tim-1/pkg/shipping"
 	"project-app-ecommerce-golang-tim-1/pkg/utils"
 
 	"go.uber.org/zap"
@@ -60,7 +64,22 @@ func main() {
 		config.SMTPEmail,
 		config.SMTPPassword,
 	)
-	router := wire.Wiring(repo, mLogger, mAuth, logger, config, emailSender)
+	payments := payment.NewRegistry(config.PaymentMethods)
+	if config.PaymentMockEnabled {
+		payments.Register("mock", payment.NewMockProvider("/api/v1/payments/mock/charges", config.MockWebhookSecret))
+	}
+	carriers := shipping.NewRegistry()
+	if config.ShippingMockEnabled {
+		carriers.Register("mock", shipping.NewMockCarrier(config.MockCarrierStep))
+	}
+	router := wire.Wiring(repo, mLogger, mAuth, logger, config, emailSender, payments, carriers)
+
+	// cancel orders that were not paid in time and follow parcels on their way
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	orders := usecase.NewOrderService(repo, logger, config, emailSender, payments, carriers)
+	go cmd.OrderExpiryWorker(workerCtx, orders, config.OrderExpiryInterval, logger)
+	go cmd.ShipmentTrackingWorker(workerCtx, orders, config.TrackingInterval, logger)
 
 	cmd.ApiServer(config, logger, router)
 }
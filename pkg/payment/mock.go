@@ -0,0 +1,155 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"project-app-ecommerce-golang-tim-1/pkg/utils"
+	"sync"
+)
+
+// MockSignatureHeader carries the HMAC of mock webhook bodies.
+const MockSignatureHeader = "X-Mock-Signature"
+
+// MockProvider is an in-memory gateway for local development and tests.
+// Charges stay pending until they are settled with Complete or Fail. Ids are
+// random so they never clash with charges stored before a restart, which the
+// gateway itself forgets.
+type MockProvider struct {
+	mu      sync.Mutex
+	baseURL string
+	secret  string
+	charges map[string]*Charge
+}
+
+// NewMockProvider creates a mock gateway whose payment pages live under
+// baseURL and whose webhooks are signed with webhookSecret.
+func NewMockProvider(baseURL, webhookSecret string) *MockProvider {
+	return &MockProvider{baseURL: baseURL, secret: webhookSecret, charges: map[string]*Charge{}}
+}
+
+type mockWebhook struct {
+	ID             string  `json:"id"`
+	ChargeID       string  `json:"charge_id"`
+	Reference      string  `json:"reference"`
+	Status         Status  `json:"status"`
+	Amount         float64 `json:"amount"`
+	RefundedAmount float64 `json:"refunded_amount"`
+}
+
+func (m *MockProvider) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	if !VerifyHMAC(m.secret, body, header.Get(MockSignatureHeader)) {
+		return nil, ErrInvalidSignature
+	}
+	var w mockWebhook
+	if err := json.Unmarshal(body, &w); err != nil {
+		return nil, err
+	}
+	if w.ID == "" || w.ChargeID == "" {
+		return nil, errors.New("webhook is missing the event or charge id")
+	}
+	return &Event{ID: w.ID, Charge: Charge{ID: w.ChargeID, Reference: w.Reference, Status: w.Status, Amount: w.Amount, RefundedAmount: w.RefundedAmount}}, nil
+}
+
+// Webhook builds the signed webhook the mock gateway sends for the current
+// state of a charge.
+func (m *MockProvider) Webhook(id string) (http.Header, []byte, error) {
+	m.mu.Lock()
+	ch, ok := m.charges[id]
+	if !ok {
+		m.mu.Unlock()
+		return nil, nil, ErrChargeNotFound
+	}
+	eventID, err := utils.GenerateRandomToken(12)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, nil, err
+	}
+	w := mockWebhook{ID: "evt_" + eventID, ChargeID: ch.ID, Reference: ch.Reference, Status: ch.Status, Amount: ch.Amount, RefundedAmount: ch.RefundedAmount}
+	m.mu.Unlock()
+
+	body, err := json.Marshal(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set(MockSignatureHeader, SignHMAC(m.secret, body))
+	return header, body, nil
+}
+
+func (m *MockProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	token, err := utils.GenerateRandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+	id := "mock_" + token
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch := &Charge{ID: id, Reference: req.Reference, Amount: req.Amount, Status: StatusPending, PaymentURL: m.baseURL + "/" + id}
+	m.charges[id] = ch
+	c := *ch
+	return &c, nil
+}
+
+func (m *MockProvider) GetCharge(ctx context.Context, id string) (*Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch, ok := m.charges[id]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	c := *ch
+	return &c, nil
+}
+
+func (m *MockProvider) Refund(ctx context.Context, id string, amount float64) (*Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch, ok := m.charges[id]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if ch.Status != StatusPaid && ch.Status != StatusPartiallyRefunded {
+		return nil, fmt.Errorf("cannot refund a %s charge", ch.Status)
+	}
+	if amount <= 0 || ch.RefundedAmount+amount > ch.Amount {
+		return nil, errors.New("refund exceeds the charged amount")
+	}
+	ch.RefundedAmount += amount
+	ch.Status = StatusPartiallyRefunded
+	if ch.RefundedAmount >= ch.Amount {
+		ch.Status = StatusRefunded
+	}
+	c := *ch
+	return &c, nil
+}
+
+// Complete settles a pending charge as paid, as if the customer paid.
+func (m *MockProvider) Complete(id string) (*Charge, error) {
+	return m.settle(id, StatusPaid)
+}
+
+// Fail settles a pending charge as failed.
+func (m *MockProvider) Fail(id string) (*Charge, error) {
+	return m.settle(id, StatusFailed)
+}
+
+func (m *MockProvider) settle(id string, status Status) (*Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch, ok := m.charges[id]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if ch.Status != StatusPending {
+		return nil, fmt.Errorf("charge is already %s", ch.Status)
+	}
+	ch.Status = status
+	c := *ch
+	return &c, nil
+}
@@ -0,0 +1,83 @@
+// Package payment abstracts the gateways that collect money for orders.
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+)
+
+// Methods without a provider. MethodCOD is cash on delivery: the courier
+// collects the money. MethodWallet and MethodGiftCard mark orders paid in
+// full from the customer's store credit or a gift card.
+const (
+	MethodCOD      = "cod"
+	MethodWallet   = "wallet"
+	MethodGiftCard = "gift_card"
+)
+
+// Status is the state of a charge as reported by its provider.
+type Status string
+
+const (
+	StatusPending           Status = "pending"
+	StatusPaid              Status = "paid"
+	StatusFailed            Status = "failed"
+	StatusExpired           Status = "expired"
+	StatusRefunded          Status = "refunded"
+	StatusPartiallyRefunded Status = "partially_refunded"
+)
+
+var (
+	ErrChargeNotFound   = errors.New("charge not found")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+type ChargeRequest struct {
+	Reference string // our order number
+	Amount    float64
+	Email     string
+}
+
+type Charge struct {
+	ID             string
+	Reference      string
+	Amount         float64
+	RefundedAmount float64
+	Status         Status
+	PaymentURL     string // where the customer completes the payment
+}
+
+// Event is a charge update pushed by a provider's webhook.
+type Event struct {
+	ID     string // unique per provider, used to drop redeliveries
+	Charge Charge
+}
+
+// Provider is a payment gateway.
+type Provider interface {
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	GetCharge(ctx context.Context, id string) (*Charge, error)
+	Refund(ctx context.Context, id string, amount float64) (*Charge, error)
+	// ParseWebhook verifies the signature of a webhook request and decodes
+	// it. It returns ErrInvalidSignature when the request is not authentic.
+	ParseWebhook(header http.Header, body []byte) (*Event, error)
+}
+
+// SignHMAC returns the hex encoded HMAC-SHA256 of body.
+func SignHMAC(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMAC reports whether signature is the HMAC-SHA256 of body, in constant time.
+func VerifyHMAC(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(SignHMAC(secret, body)), []byte(signature))
+}
@@ -0,0 +1,45 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Registry maps payment methods to providers. Only methods on the allow-list
+// can be used, even if a provider is registered for them.
+type Registry struct {
+	allowed   map[string]bool
+	providers map[string]Provider
+}
+
+func NewRegistry(allowed []string) *Registry {
+	r := &Registry{allowed: map[string]bool{}, providers: map[string]Provider{}}
+	for _, m := range allowed {
+		if m = strings.TrimSpace(m); m != "" {
+			r.allowed[m] = true
+		}
+	}
+	return r
+}
+
+// Register sets the provider collecting payments for method.
+func (r *Registry) Register(method string, p Provider) {
+	r.providers[method] = p
+}
+
+// Allowed reports whether method is on the allow-list.
+func (r *Registry) Allowed(method string) bool {
+	return r.allowed[method]
+}
+
+// Get returns the provider for method if the method is allowed.
+func (r *Registry) Get(method string) (Provider, error) {
+	if !r.allowed[method] {
+		return nil, fmt.Errorf("payment method %s is not supported", method)
+	}
+	p, ok := r.providers[method]
+	if !ok {
+		return nil, fmt.Errorf("payment method %s is not available", method)
+	}
+	return p, nil
+}
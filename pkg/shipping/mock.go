@@ -0,0 +1,76 @@
+package shipping
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// mockJourney is the route every mock parcel takes.
+var mockJourney = []struct {
+	status      Status
+	description string
+	location    string
+}{
+	{StatusPickedUp, "Parcel picked up from the seller", "Origin hub"},
+	{StatusInTransit, "Parcel is on its way to the destination hub", "Sorting center"},
+	{StatusOutForDelivery, "Courier is delivering the parcel", "Destination hub"},
+	{StatusDelivered, "Parcel delivered", "Destination"},
+}
+
+// MockCarrier is an in-memory carrier for local development and tests.
+// Parcels move one step along their journey every step after booking, so
+// polling sees them progress to delivered on their own.
+type MockCarrier struct {
+	mu     sync.Mutex
+	seq    int
+	step   time.Duration
+	booked map[string]time.Time
+}
+
+func NewMockCarrier(step time.Duration) *MockCarrier {
+	return &MockCarrier{step: step, booked: map[string]time.Time{}}
+}
+
+func (m *MockCarrier) CreateShipment(ctx context.Context, req ShipmentRequest) (*Label, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	number := fmt.Sprintf("MOCK%08d", m.seq)
+	m.booked[number] = time.Now()
+	return &Label{TrackingNumber: number}, nil
+}
+
+// Track starts tracking a parcel booked outside the carrier, as if it was
+// picked up at bookedAt.
+func (m *MockCarrier) Track(trackingNumber string, bookedAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.booked[trackingNumber] = bookedAt
+}
+
+func (m *MockCarrier) GetTrackingEvents(ctx context.Context, trackingNumber string) ([]TrackingEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bookedAt, ok := m.booked[trackingNumber]
+	if !ok {
+		return nil, ErrShipmentNotFound
+	}
+	now := time.Now()
+	var events []TrackingEvent
+	for i, j := range mockJourney {
+		at := bookedAt.Add(time.Duration(i) * m.step)
+		if at.After(now) {
+			break
+		}
+		events = append(events, TrackingEvent{
+			ID:          fmt.Sprintf("%s-%d", trackingNumber, i+1),
+			Status:      j.status,
+			Description: j.description,
+			Location:    j.location,
+			OccurredAt:  at,
+		})
+	}
+	return events, nil
+}
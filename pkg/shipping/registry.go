@@ -0,0 +1,38 @@
+package shipping
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Registry maps carrier names, as stored on shipments, to carriers.
+type Registry struct {
+	carriers map[string]Carrier
+}
+
+func NewRegistry() *Registry {
+	return &Registry{carriers: map[string]Carrier{}}
+}
+
+// Register sets the carrier used for shipments sent with name.
+func (r *Registry) Register(name string, c Carrier) {
+	r.carriers[name] = c
+}
+
+func (r *Registry) Get(name string) (Carrier, error) {
+	c, ok := r.carriers[name]
+	if !ok {
+		return nil, fmt.Errorf("carrier %s is not available", name)
+	}
+	return c, nil
+}
+
+// Names returns the registered carrier names in order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.carriers))
+	for name := range r.carriers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
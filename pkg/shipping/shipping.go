@@ -0,0 +1,49 @@
+// Package shipping abstracts the carriers that deliver orders.
+package shipping
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Status is the state of a parcel as reported by its carrier.
+type Status string
+
+const (
+	StatusPickedUp       Status = "picked_up"
+	StatusInTransit      Status = "in_transit"
+	StatusOutForDelivery Status = "out_for_delivery"
+	StatusDelivered      Status = "delivered"
+	StatusFailedAttempt  Status = "failed_attempt"
+)
+
+var ErrShipmentNotFound = errors.New("shipment not found")
+
+type ShipmentRequest struct {
+	Reference string // our order number
+	Name      string
+	Address   string
+	Weight    int // grams
+}
+
+// Label is a shipment booked with a carrier.
+type Label struct {
+	TrackingNumber string
+}
+
+// TrackingEvent is one step of a parcel's journey.
+type TrackingEvent struct {
+	ID          string // unique per tracking number, used to skip known events
+	Status      Status
+	Description string
+	Location    string
+	OccurredAt  time.Time
+}
+
+// Carrier is a delivery company.
+type Carrier interface {
+	CreateShipment(ctx context.Context, req ShipmentRequest) (*Label, error)
+	// GetTrackingEvents returns every event of the parcel so far, oldest first.
+	GetTrackingEvents(ctx context.Context, trackingNumber string) ([]TrackingEvent, error)
+}
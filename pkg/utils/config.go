@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -19,6 +22,20 @@ type Configuration struct {
 	SMTPPort            int
 	SMTPEmail           string
 	SMTPPassword        string
+	TaxRate             float64
+	OrderNumberPrefix   string
+	SupportEmail        string
+	PaymentMethods      []string
+	MockWebhookSecret   string
+	PaymentMockEnabled  bool                     // dev only: anyone can settle mock charges
+	PaymentDeadline     time.Duration            // default time to pay before an order is cancelled
+	PaymentDeadlines    map[string]time.Duration // per payment method overrides
+	OrderExpiryInterval time.Duration
+	CODMaxOrderValue    float64 // 0 means no limit
+	CODMaxCancelled     int     // cancelled COD orders after which a customer loses COD, 0 means never
+	TrackingInterval    time.Duration
+	ShippingMockEnabled bool          // dev only: mock parcels deliver themselves
+	MockCarrierStep     time.Duration // how long a mock parcel takes per tracking step
 }
 
 type DatabaseConfig struct {
@@ -66,9 +83,53 @@ func ReadConfiguration() (Configuration, error) {
 			MaxIdleTime:  viper.GetInt("DB_MAX_IDLE_TIME"),
 			MaxLifeTime:  viper.GetInt("DB_MAX_LIFE_TIME"),
 		},
-		SMTPHost:     viper.GetString("SMTPHost"),
-		SMTPPort:     viper.GetInt("SMTPPort"),
-		SMTPEmail:    viper.GetString("SMTPEmail"),
-		SMTPPassword: viper.GetString("SMTPPassword"),
+		SMTPHost:            viper.GetString("SMTPHost"),
+		SMTPPort:            viper.GetInt("SMTPPort"),
+		SMTPEmail:           viper.GetString("SMTPEmail"),
+		SMTPPassword:        viper.GetString("SMTPPassword"),
+		TaxRate:             viper.GetFloat64("TAX_RATE"),
+		OrderNumberPrefix:   viper.GetString("ORDER_NUMBER_PREFIX"),
+		SupportEmail:        viper.GetString("SUPPORT_EMAIL"),
+		PaymentMethods:      strings.Split(viper.GetString("PAYMENT_METHODS"), ","),
+		MockWebhookSecret:   viper.GetString("PAYMENT_MOCK_WEBHOOK_SECRET"),
+		PaymentMockEnabled:  viper.GetBool("PAYMENT_MOCK_ENABLED"),
+		PaymentDeadline:     viper.GetDuration("PAYMENT_DEADLINE"),
+		PaymentDeadlines:    parseDurations(viper.GetString("PAYMENT_DEADLINES")),
+		OrderExpiryInterval: viper.GetDuration("ORDER_EXPIRY_INTERVAL"),
+		CODMaxOrderValue:    viper.GetFloat64("COD_MAX_ORDER_VALUE"),
+		CODMaxCancelled:     viper.GetInt("COD_MAX_CANCELLED"),
+		TrackingInterval:    viper.GetDuration("SHIPMENT_TRACKING_INTERVAL"),
+		ShippingMockEnabled: viper.GetBool("SHIPPING_MOCK_ENABLED"),
+		MockCarrierStep:     durationOr(viper.GetDuration("SHIPPING_MOCK_STEP"), defaultMockCarrierStep),
 	}, nil
 }
+
+// defaultMockCarrierStep keeps mock parcels from being delivered on the
+// first tracking poll when SHIPPING_MOCK_STEP is not set.
+const defaultMockCarrierStep = 10 * time.Minute
+
+// durationOr returns d, or def when d is not positive.
+func durationOr(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+// parseDurations reads "key=duration" pairs separated by commas, such as
+// "gopay=15m,bank_transfer=24h". Malformed pairs are skipped.
+func parseDurations(s string) map[string]time.Duration {
+	out := map[string]time.Duration{}
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		out[strings.TrimSpace(k)] = d
+	}
+	return out
+}
@@ -21,3 +21,20 @@ func GenerateRandomToken(length int) (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+// codeAlphabet leaves out characters that are easily misread, such as 0/O and 1/I.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateCode returns a random code of length characters that people can
+// read out and type, e.g. for gift cards.
+func GenerateCode(length int) (string, error) {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	for i, b := range bytes {
+		// 256 is a multiple of the alphabet size, so every character is equally likely
+		bytes[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(bytes), nil
+}